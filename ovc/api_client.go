@@ -0,0 +1,540 @@
+package ovc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gig-tech/ovc-sdk-go/v3/ovc"
+)
+
+const (
+	taskEndpoint = "/system/task/get"
+	// taskPollMinInterval is the time before the first poll for a task result
+	taskPollMinInterval = 250 * time.Millisecond
+	nginxBadRequestBody = "<html>\r\n<head><title>400 Bad Request</title></head>\r\n<body>\r\n<center><h1>400 Bad Request</h1></center>\r\n<hr><center>nginx/1.17.6</center>\r\n</body>\r\n</html>\r\n"
+)
+
+// apiClient issues calls against the G8 API on behalf of the SDK services.
+// It follows the same async request/task protocol as the SDK client, but
+// keeps the HTTP status of failed calls so they can be classified.
+// When ctx has a deadline, it replaces the fixed ResponseTimeout of the calls.
+// ctx derives from the StopContext of the provider, so calls end when Terraform is interrupted.
+type apiClient struct {
+	ctx        context.Context
+	serverURL  string
+	tokens     tokenSource
+	logger     ovc.Logger
+	httpClient *http.Client
+	pools      requestPools
+	retry      retryPolicy
+	locations  *locationResolver
+	cache      *readCache
+}
+
+// apiOptions are the provider settings of the api client
+type apiOptions struct {
+	concurrency concurrencyLimits
+	retry       retryPolicy
+	location    string
+}
+
+// concurrencyLimits are the maximum numbers of concurrent requests per pool, limits that
+// are not set default to the one of model calls
+type concurrencyLimits struct {
+	model       int
+	operational int
+	data        int
+	taskPolls   int
+}
+
+// requestPools bound the concurrent requests per ResponseTimeout class of the SDK, so long running
+// operational and data calls do not starve the fast model calls. Polling the result of a task
+// does not count against the pool of the call that started it.
+type requestPools struct {
+	model       chan struct{}
+	operational chan struct{}
+	data        chan struct{}
+	taskPolls   chan struct{}
+}
+
+func newRequestPools(limits concurrencyLimits) requestPools {
+	if limits.model < 1 {
+		limits.model = 1
+	}
+	size := func(limit int) int {
+		if limit < 1 {
+			return limits.model
+		}
+		return limit
+	}
+	return requestPools{
+		model:       make(chan struct{}, limits.model),
+		operational: make(chan struct{}, size(limits.operational)),
+		data:        make(chan struct{}, size(limits.data)),
+		taskPolls:   make(chan struct{}, size(limits.taskPolls)),
+	}
+}
+
+// pool returns the pool of calls of the given class
+func (p requestPools) pool(timeout ovc.ResponseTimeout) chan struct{} {
+	switch timeout {
+	case ovc.OperationalActionTimeout:
+		return p.operational
+	case ovc.DataActionTimeout:
+		return p.data
+	}
+	return p.model
+}
+
+// retryPolicy bounds how often and how long throttled or failed requests are retried
+type retryPolicy struct {
+	maxRetries int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+	// maxPollInterval caps the time between polls for the result of a task
+	maxPollInterval time.Duration
+}
+
+// backoff returns the time to wait before the given retry attempt, starting at 1. It doubles with
+// every attempt up to maxBackoff, with jitter so throttled requests are not retried in lockstep.
+func (r retryPolicy) backoff(attempt int) time.Duration {
+	return withJitter(exponential(r.minBackoff, attempt, r.maxBackoff))
+}
+
+// retryDelay returns the delay the G8 asked for in its response, or the backoff of the attempt
+func (r retryPolicy) retryDelay(attempt int, resp apiResponse) time.Duration {
+	if resp.retryAfter > 0 {
+		return resp.retryAfter
+	}
+	return r.backoff(attempt)
+}
+
+// pollInterval returns the time to wait before the given poll for a task result, starting at 1.
+// The first polls are quick, so short model tasks return fast, long tasks are polled every maxPollInterval.
+func (r retryPolicy) pollInterval(poll int) time.Duration {
+	return exponential(taskPollMinInterval, poll, r.maxPollInterval)
+}
+
+// exponential returns base doubled for every attempt after the first, capped at max but not below base
+func exponential(base time.Duration, attempt int, max time.Duration) time.Duration {
+	if max < base {
+		max = base
+	}
+	d := base
+	for i := 1; i < attempt && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		return max
+	}
+	return d
+}
+
+// withJitter returns a random duration between half of d and d
+func withJitter(d time.Duration) time.Duration {
+	half := d / 2
+	if half <= 0 {
+		return d
+	}
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// apiResponse is a response of the G8 API
+type apiResponse struct {
+	status int
+	body   []byte
+	// retryAfter is the delay requested by the Retry-After header of the response
+	retryAfter time.Duration
+}
+
+// parseRetryAfter returns the delay of a Retry-After header in seconds or as HTTP date
+func parseRetryAfter(value string) time.Duration {
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if d := time.Until(date); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+func newAPIClient(ctx context.Context, serverURL string, tokens tokenSource, httpClient *http.Client, logger ovc.Logger, options apiOptions) *apiClient {
+	return &apiClient{
+		ctx:        ctx,
+		serverURL:  serverURL,
+		tokens:     tokens,
+		logger:     logger,
+		httpClient: httpClient,
+		pools:      newRequestPools(options.concurrency),
+		retry:      options.retry,
+		locations:  &locationResolver{defaultLocation: options.location},
+		cache:      newReadCache(logger),
+	}
+}
+
+// withContext returns a copy of the api client that issues its calls within ctx
+func (a *apiClient) withContext(ctx context.Context) *apiClient {
+	c := *a
+	c.ctx = ctx
+	return &c
+}
+
+// sleep pauses for d, it returns early with an error when the context is done
+func (a *apiClient) sleep(d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-a.ctx.Done():
+		return a.ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// useServices points all services of the SDK client to the api client
+func (a *apiClient) useServices(client *ovc.Client) {
+	client.Machines = &machineService{api: a}
+	client.CloudSpaces = &cloudSpaceService{api: a}
+	client.Accounts = &accountService{api: a}
+	client.Disks = &diskService{api: a}
+	client.Portforwards = &forwardingService{api: a}
+	client.Templates = &templateService{api: a}
+	client.Sizes = &sizesService{api: a}
+	client.Images = &imageService{api: a}
+	client.Ipsec = &ipsecService{api: a}
+	client.ExternalNetworks = &externalNetworkService{api: a}
+	client.Locations = &locationService{api: a}
+}
+
+// doRequest sends a single authenticated request within a slot of pool
+func (a *apiClient) doRequest(pool chan struct{}, endpoint string, body []byte) (apiResponse, error) {
+	select {
+	case pool <- struct{}{}:
+		defer func() { <-pool }()
+	case <-a.ctx.Done():
+		return apiResponse{}, a.ctx.Err()
+	}
+
+	req, err := http.NewRequestWithContext(a.ctx, http.MethodPost, a.serverURL+endpoint, bytes.NewReader(body))
+	if err != nil {
+		return apiResponse{}, err
+	}
+	token, err := a.tokens.token()
+	if err != nil {
+		a.logger.Errorf("Could not make JWT: %s", err)
+		return apiResponse{}, err
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("bearer %s", token))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return apiResponse{}, err
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return apiResponse{}, err
+	}
+	a.logger.Debugf("OVC call: %s", endpoint)
+	a.logger.Debugf("OVC response status: %s", resp.Status)
+	a.logger.Debugf("OVC response body: %s", string(respBody))
+	return apiResponse{
+		status:     resp.StatusCode,
+		body:       respBody,
+		retryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}, nil
+}
+
+// refreshToken makes the token source obtain a new JWT after the G8 rejected the current one
+func (a *apiClient) refreshToken() error {
+	a.logger.Infof("JWT was rejected by the G8, refreshing it")
+	if err := a.tokens.refresh(); err != nil {
+		return fmt.Errorf("error refreshing JWT: %w", err)
+	}
+	return nil
+}
+
+// location returns the G8 location with the given code, see locationResolver.resolve
+func (a *apiClient) location(name string) (*ovc.LocationInfo, error) {
+	return a.locations.resolve(&locationService{api: a}, name)
+}
+
+// asyncBody marshals in to a JSON object with the "_async" flag set
+func asyncBody(in interface{}) ([]byte, error) {
+	jsonMap := make(map[string]interface{})
+	if in != nil {
+		raw, err := json.Marshal(in)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(raw, &jsonMap); err != nil {
+			return nil, err
+		}
+	}
+	jsonMap["_async"] = true
+	return json.Marshal(jsonMap)
+}
+
+// post calls an endpoint asynchronously and waits for the result of the task
+func (a *apiClient) post(endpoint string, in interface{}, timeout ovc.ResponseTimeout) ([]byte, error) {
+	if !isReadEndpoint(endpoint) {
+		// a change of a machine, disk or cloudspace can show in any of the cached lists,
+		// the cache is also dropped when the call failed as the change may have been made anyway
+		defer a.cache.invalidateAll()
+	}
+	body, err := asyncBody(in)
+	if err != nil {
+		return nil, err
+	}
+	taskID, err := a.startTask(endpoint, body, timeout)
+	if err != nil {
+		return nil, err
+	}
+	return a.waitForTask(endpoint, taskID, timeout)
+}
+
+// postInto calls an endpoint and unmarshals the task result into out
+func (a *apiClient) postInto(endpoint string, in interface{}, timeout ovc.ResponseTimeout, out interface{}) error {
+	result, err := a.post(endpoint, in, timeout)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(result, out)
+}
+
+// postCached is postInto for list endpoints, the result is served from the read cache
+// until one of the mutating calls invalidates the endpoint
+func (a *apiClient) postCached(endpoint string, in interface{}, timeout ovc.ResponseTimeout, out interface{}) error {
+	result, err := a.cache.get(endpoint, in, func() ([]byte, error) {
+		return a.post(endpoint, in, timeout)
+	})
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(result, out)
+}
+
+// invalidate drops the cached results of the given list endpoints
+func (a *apiClient) invalidate(endpoints ...string) {
+	a.cache.invalidate(endpoints...)
+}
+
+// isReadEndpoint reports whether a call of the endpoint only reads from the G8, like list and get calls
+func isReadEndpoint(endpoint string) bool {
+	action := endpoint[strings.LastIndex(endpoint, "/")+1:]
+	return strings.HasPrefix(action, "list") || strings.HasPrefix(action, "get")
+}
+
+// startTask issues the async request and returns the GUID of the G8 task
+func (a *apiClient) startTask(endpoint string, body []byte, timeout ovc.ResponseTimeout) (string, error) {
+	retries := 0
+	refreshed := false
+	for {
+		resp, err := a.doRequest(a.pools.pool(timeout), endpoint, body)
+		status, respBody := resp.status, resp.body
+		if err != nil {
+			a.logger.Errorf("Error doing G8 Api request: %s", err)
+			if retries < a.retry.maxRetries && a.ctx.Err() == nil {
+				retries++
+				if err := a.sleep(a.retry.backoff(retries)); err != nil {
+					return "", err
+				}
+				continue
+			}
+			return "", err
+		}
+		switch {
+		case status == http.StatusBadRequest && string(respBody) == nginxBadRequestBody,
+			status == http.StatusTooManyRequests:
+			// Sometimes nginx returns 400 for no reason, or the G8 is throttling
+			if retries >= a.retry.maxRetries {
+				return "", newAPIError(endpoint, status, respBody)
+			}
+			retries++
+			if err := a.sleep(a.retry.retryDelay(retries, resp)); err != nil {
+				return "", err
+			}
+			continue
+		case status == http.StatusUnauthorized && !refreshed:
+			// the JWT was revoked or expired, obtain a new one and try once more
+			refreshed = true
+			if err := a.refreshToken(); err != nil {
+				return "", err
+			}
+			continue
+		case status > http.StatusAccepted:
+			err := newAPIError(endpoint, status, respBody)
+			a.logger.Errorf("Request failed with error: %s", err)
+			return "", err
+		}
+		return strings.Replace(string(respBody), "\"", "", -1), nil
+	}
+}
+
+// waitForTask polls the G8 for the result of an async task
+func (a *apiClient) waitForTask(endpoint string, taskID string, timeout ovc.ResponseTimeout) ([]byte, error) {
+	taskJSON, err := json.Marshal(struct {
+		TaskID string `json:"taskguid"`
+	}{
+		TaskID: taskID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(time.Duration(timeout))
+	if ctxDeadline, ok := a.ctx.Deadline(); ok {
+		deadline = ctxDeadline
+	}
+	ctx, cancel := context.WithDeadline(a.ctx, deadline)
+	defer cancel()
+	a = a.withContext(ctx)
+
+	var result []interface{}
+	retries := 0
+	polls := 0
+	notFoundSeen := false
+	refreshed := false
+	for {
+		resp, err := a.doRequest(a.pools.taskPolls, taskEndpoint, taskJSON)
+		status, resultBody := resp.status, resp.body
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, a.taskInterrupted(endpoint, taskID)
+			}
+			a.logger.Errorf("Error getting task result: %s", err)
+			if retries < a.retry.maxRetries {
+				retries++
+				if a.sleep(a.retry.backoff(retries)) != nil {
+					return nil, a.taskInterrupted(endpoint, taskID)
+				}
+				continue
+			}
+			return nil, err
+		}
+
+		switch {
+		case status == http.StatusNotFound && !notFoundSeen:
+			// API servers prior 2.5.6 can report a task as not found right after it was started
+			notFoundSeen = true
+			polls++
+			if a.sleep(a.retry.pollInterval(polls)) != nil {
+				return nil, a.taskInterrupted(endpoint, taskID)
+			}
+			continue
+		case status == http.StatusNotFound:
+			_, message := parseErrorMessage(resultBody)
+			err := &taskLostError{Endpoint: endpoint, TaskID: taskID, Message: message}
+			a.logger.Errorf("Task failed: %s", err)
+			return nil, err
+		case status == http.StatusBadRequest, status == http.StatusTooManyRequests:
+			if retries >= a.retry.maxRetries {
+				return nil, newTaskPollError(endpoint, taskID, status, resultBody)
+			}
+			retries++
+			if a.sleep(a.retry.retryDelay(retries, resp)) != nil {
+				return nil, a.taskInterrupted(endpoint, taskID)
+			}
+			continue
+		case status == http.StatusUnauthorized && !refreshed:
+			refreshed = true
+			if err := a.refreshToken(); err != nil {
+				return nil, err
+			}
+			continue
+		case status > http.StatusAccepted:
+			err := newTaskPollError(endpoint, taskID, status, resultBody)
+			a.logger.Errorf("Task failed: %s", err)
+			return nil, err
+		}
+
+		if len(resultBody) != 0 {
+			if err := json.Unmarshal(resultBody, &result); err != nil {
+				a.logger.Errorf("Could not marshal json body into object: %s", err)
+				return resultBody, err
+			}
+			if len(result) != 0 {
+				break
+			}
+		}
+		polls++
+		if a.sleep(a.retry.pollInterval(polls)) != nil {
+			return nil, a.taskInterrupted(endpoint, taskID)
+		}
+	}
+
+	success, ok := result[0].(bool)
+	if !ok {
+		return nil, fmt.Errorf("Task response is incorrect taskId %v \n expected response in form [True/False, taskResult], received: \n %v", taskID, result)
+	}
+	if !success {
+		err := newTaskError(endpoint, taskID, result)
+		a.logger.Errorf("%s", err)
+		return nil, err
+	}
+	return json.Marshal(result[1])
+}
+
+// newTaskPollError returns the error of a failed call to get the result of a task
+func newTaskPollError(endpoint string, taskID string, status int, body []byte) error {
+	err := newAPIError(endpoint, status, body)
+	err.TaskID = taskID
+	return err
+}
+
+// taskInterrupted logs and returns the error for a task that was not waited for until it finished,
+// because the operation timed out or Terraform was interrupted
+func (a *apiClient) taskInterrupted(endpoint string, taskID string) error {
+	err := &taskInterruptedError{Endpoint: endpoint, TaskID: taskID, Err: a.ctx.Err()}
+	a.logger.Warnf("%s", err)
+	return err
+}
+
+// taskResult polls the G8 once for the result of a task started earlier, like one that was interrupted.
+// done is false while the task is still running.
+func (a *apiClient) taskResult(taskID string) (result []byte, done bool, err error) {
+	taskJSON, err := json.Marshal(map[string]string{"taskguid": taskID})
+	if err != nil {
+		return nil, false, err
+	}
+	resp, err := a.doRequest(a.pools.taskPolls, taskEndpoint, taskJSON)
+	if err != nil {
+		return nil, false, err
+	}
+	status, body := resp.status, resp.body
+	if status == http.StatusNotFound {
+		_, message := parseErrorMessage(body)
+		return nil, false, &taskLostError{Endpoint: taskEndpoint, TaskID: taskID, Message: message}
+	}
+	if status > http.StatusAccepted {
+		return nil, false, newAPIError(taskEndpoint, status, body)
+	}
+	var task []interface{}
+	if len(body) != 0 {
+		if err := json.Unmarshal(body, &task); err != nil {
+			return nil, false, err
+		}
+	}
+	if len(task) == 0 {
+		return nil, false, nil
+	}
+	if success, _ := task[0].(bool); !success {
+		return nil, true, newTaskError(taskEndpoint, taskID, task)
+	}
+	if len(task) < 2 {
+		return nil, true, fmt.Errorf("task %s has no result", taskID)
+	}
+	result, err = json.Marshal(task[1])
+	return result, true, err
+}
@@ -0,0 +1,150 @@
+package ovc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gig-tech/ovc-sdk-go/v3/ovc"
+	"github.com/sirupsen/logrus"
+)
+
+// newTestAPIClient returns an api client for a G8 stand-in
+func newTestAPIClient(t *testing.T, ctx context.Context, server *httptest.Server, retry retryPolicy) *apiClient {
+	t.Helper()
+	jwt := unsignedJWT(t, map[string]interface{}{"preferred_username": "ci", "exp": time.Now().Add(time.Hour).Unix()})
+	tokens, _, err := newTokenSource(authConfig{identityProvider: identityProviderOIDC, usernameClaim: "preferred_username", jwt: jwt}, server.Client(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := ovc.LogrusAdapter{FieldLogger: logrus.New()}
+	return newAPIClient(ctx, server.URL, tokens, server.Client(), logger, apiOptions{concurrency: concurrencyLimits{model: 1}, retry: retry})
+}
+
+func TestRequestPools(t *testing.T) {
+	pools := newRequestPools(concurrencyLimits{model: 4, data: 1})
+	if cap(pools.model) != 4 || cap(pools.operational) != 4 || cap(pools.data) != 1 || cap(pools.taskPolls) != 4 {
+		t.Errorf("unexpected pool sizes %d, %d, %d, %d", cap(pools.model), cap(pools.operational), cap(pools.data), cap(pools.taskPolls))
+	}
+	if pools.pool(ovc.DataActionTimeout) != pools.data || pools.pool(ovc.OperationalActionTimeout) != pools.operational ||
+		pools.pool(ovc.ModelActionTimeout) != pools.model {
+		t.Errorf("calls are not assigned to the pool of their class")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	retry := retryPolicy{minBackoff: 100 * time.Millisecond, maxBackoff: time.Second, maxPollInterval: 2 * time.Second}
+	for attempt, want := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 4: 800 * time.Millisecond, 5: time.Second, 50: time.Second} {
+		for i := 0; i < 10; i++ {
+			if d := retry.backoff(attempt); d < want/2 || d > want {
+				t.Errorf("backoff(%d) = %s, want between %s and %s", attempt, d, want/2, want)
+			}
+		}
+	}
+	for poll, want := range map[int]time.Duration{1: 250 * time.Millisecond, 2: 500 * time.Millisecond, 4: 2 * time.Second, 10: 2 * time.Second} {
+		if d := retry.pollInterval(poll); d != want {
+			t.Errorf("pollInterval(%d) = %s, want %s", poll, d, want)
+		}
+	}
+}
+
+func TestRetryAfterIsHonoured(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == taskEndpoint {
+			fmt.Fprint(w, `[true, 1]`)
+			return
+		}
+		if atomic.AddInt32(&calls, 1) == 1 {
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		fmt.Fprint(w, `"guid"`)
+	}))
+	defer server.Close()
+
+	api := newTestAPIClient(t, context.Background(), server, retryPolicy{maxRetries: 3, minBackoff: time.Millisecond, maxBackoff: time.Millisecond})
+	start := time.Now()
+	if _, err := api.post("/cloudapi/machines/list", nil, ovc.ModelActionTimeout); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second {
+		t.Errorf("retried after %s, want at least the 1s of Retry-After", elapsed)
+	}
+}
+
+func TestRetriesAreBounded(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	api := newTestAPIClient(t, context.Background(), server, retryPolicy{maxRetries: 2, minBackoff: time.Millisecond, maxBackoff: 5 * time.Millisecond})
+	_, err := api.post("/cloudapi/machines/list", nil, ovc.ModelActionTimeout)
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected the 429 after the last retry, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("%d requests, want 1 and 2 retries", calls)
+	}
+}
+
+func TestShortTasksArePolledQuickly(t *testing.T) {
+	var polls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != taskEndpoint {
+			fmt.Fprint(w, `"guid"`)
+			return
+		}
+		if atomic.AddInt32(&polls, 1) < 3 {
+			fmt.Fprint(w, `[]`)
+			return
+		}
+		fmt.Fprint(w, `[true, 7]`)
+	}))
+	defer server.Close()
+
+	api := newTestAPIClient(t, context.Background(), server, retryPolicy{maxPollInterval: 2 * time.Second})
+	start := time.Now()
+	result, err := api.post("/cloudapi/machines/get", nil, ovc.ModelActionTimeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(result) != "7" {
+		t.Errorf("result = %s", result)
+	}
+	// polled after 250ms and 500ms, instead of twice 2s
+	if elapsed := time.Since(start); elapsed > 1500*time.Millisecond {
+		t.Errorf("task took %s to be picked up", elapsed)
+	}
+}
+
+func TestLostTaskIsNotANotFoundObject(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != taskEndpoint {
+			fmt.Fprint(w, `"guid"`)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `"Task not found"`)
+	}))
+	defer server.Close()
+
+	api := newTestAPIClient(t, context.Background(), server, retryPolicy{maxPollInterval: time.Millisecond})
+	_, err := api.post("/cloudapi/machines/create", nil, ovc.ModelActionTimeout)
+	if !isTaskLost(err) {
+		t.Fatalf("expected a lost task, got %v", err)
+	}
+	if isNotFound(err) {
+		t.Error("a lost task must not remove the resource from the state")
+	}
+}
@@ -0,0 +1,510 @@
+package ovc
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/gig-tech/ovc-sdk-go/v3/ovc"
+)
+
+// The services below implement the SDK service interfaces on top of apiClient.
+// The SDK client sends its requests with its own http.Client and hides the status of failed
+// calls, so the services mirror the SDK endpoints and requests, only errors are kept typed.
+// Only the endpoints the provider calls are implemented, the embedded SDK interfaces
+// are left nil and only complete the method sets.
+// Lookups by name wrap ovc.ErrNotFound, so callers can classify them with isNotFound.
+// Calls that change the G8 drop the read cache, see apiClient.post.
+
+type machineService struct {
+	ovc.MachineService
+	api *apiClient
+}
+
+// List all machines of a cloudspace
+func (s *machineService) List(cloudSpaceID int) (*[]ovc.Machine, error) {
+	machines := new([]ovc.Machine)
+	in := map[string]interface{}{"cloudspaceId": cloudSpaceID}
+	if err := s.api.postCached(machinesListEndpoint, in, ovc.ModelActionTimeout, machines); err != nil {
+		return nil, err
+	}
+	return machines, nil
+}
+
+// Get individual machine
+func (s *machineService) Get(id int) (*ovc.MachineInfo, error) {
+	machineInfo := new(ovc.MachineInfo)
+	in := map[string]interface{}{"machineId": id}
+	if err := s.api.postInto("/cloudapi/machines/get", in, ovc.OperationalActionTimeout, machineInfo); err != nil {
+		return nil, err
+	}
+	return machineInfo, nil
+}
+
+// GetByName gets an individual machine from its name
+func (s *machineService) GetByName(name string, cloudspaceID int) (*ovc.MachineInfo, error) {
+	machines, err := s.List(cloudspaceID)
+	if err != nil {
+		return nil, err
+	}
+	for _, mc := range *machines {
+		if mc.Name == name {
+			return s.Get(mc.ID)
+		}
+	}
+	return nil, fmt.Errorf("machine %s: %w", name, ovc.ErrNotFound)
+}
+
+// GetByReferenceID gets an individual machine from its reference ID
+func (s *machineService) GetByReferenceID(referenceID string) (*ovc.MachineInfo, error) {
+	in := map[string]interface{}{"referenceId": referenceID}
+	body, err := s.api.post("/cloudapi/machines/getByReferenceId", in, ovc.OperationalActionTimeout)
+	if err != nil {
+		return nil, err
+	}
+	machineID, err := strconv.Atoi(string(body))
+	if err != nil {
+		return nil, err
+	}
+	return s.Get(machineID)
+}
+
+// Create a new machine
+func (s *machineService) Create(machineConfig *ovc.MachineConfig) (int, error) {
+	body, err := s.api.post("/cloudapi/machines/create", *machineConfig, ovc.OperationalActionTimeout)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(string(body))
+}
+
+// Update an existing machine
+func (s *machineService) Update(machineConfig *ovc.MachineConfig) (string, error) {
+	body, err := s.api.post("/cloudapi/machines/update", *machineConfig, ovc.ModelActionTimeout)
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+// Resize an existing machine
+func (s *machineService) Resize(machineConfig *ovc.MachineConfig) (string, error) {
+	body, err := s.api.post("/cloudapi/machines/resize", *machineConfig, ovc.OperationalActionTimeout)
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+// Delete deletes an existing machine
+func (s *machineService) Delete(id int, permanently bool) error {
+	in := map[string]interface{}{"machineId": id, "permanently": permanently}
+	_, err := s.api.post("/cloudapi/machines/delete", in, ovc.OperationalActionTimeout)
+	return err
+}
+
+// Stop stops a machine
+func (s *machineService) Stop(id int, force bool) error {
+	in := map[string]interface{}{"machineId": id, "stop": force}
+	_, err := s.api.post("/cloudapi/machines/stop", in, ovc.OperationalActionTimeout)
+	return err
+}
+
+// Start starts a machine, boots from ISO if diskID is given
+func (s *machineService) Start(id int, diskID int) error {
+	in := map[string]interface{}{"machineId": id}
+	if diskID != 0 {
+		in["diskId"] = diskID
+	}
+	_, err := s.api.post("/cloudapi/machines/start", in, ovc.OperationalActionTimeout)
+	return err
+}
+
+// AddExternalIP adds external IP
+func (s *machineService) AddExternalIP(id int, externalNetworkID int) error {
+	in := map[string]interface{}{"machineId": id}
+	if externalNetworkID != 0 {
+		in["externalNetworkId"] = externalNetworkID
+	}
+	_, err := s.api.post("/cloudapi/machines/attachExternalNetwork", in, ovc.OperationalActionTimeout)
+	return err
+}
+
+// DeleteExternalIP removes external IP
+func (s *machineService) DeleteExternalIP(id int, externalNetworkID int, externalNetworkIP string) error {
+	in := map[string]interface{}{"machineId": id}
+	if externalNetworkID > 0 {
+		in["externalNetworkId"] = externalNetworkID
+		if len(externalNetworkIP) > 0 {
+			in["externalnetworkip"] = externalNetworkIP
+		}
+	}
+	_, err := s.api.post("/cloudapi/machines/detachExternalNetwork", in, ovc.OperationalActionTimeout)
+	return err
+}
+
+type cloudSpaceService struct {
+	api *apiClient
+}
+
+// List returns all cloudspaces
+func (s *cloudSpaceService) List() (*[]ovc.CloudSpaceInfo, error) {
+	cloudSpaces := new([]ovc.CloudSpaceInfo)
+	in := map[string]interface{}{"includedeleted": false}
+	if err := s.api.postInto("/cloudapi/cloudspaces/list", in, ovc.ModelActionTimeout, cloudSpaces); err != nil {
+		return nil, err
+	}
+	return cloudSpaces, nil
+}
+
+// Get individual cloudspace
+func (s *cloudSpaceService) Get(id int) (*ovc.CloudSpace, error) {
+	cloudSpace := new(ovc.CloudSpace)
+	in := map[string]interface{}{"cloudspaceId": id}
+	if err := s.api.postInto("/cloudapi/cloudspaces/get", in, ovc.ModelActionTimeout, cloudSpace); err != nil {
+		return nil, err
+	}
+	return cloudSpace, nil
+}
+
+// GetByNameAndAccount gets an individual cloudspace
+func (s *cloudSpaceService) GetByNameAndAccount(cloudSpaceName string, account string) (*ovc.CloudSpace, error) {
+	cloudSpaces, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, cp := range *cloudSpaces {
+		if cp.AccountName == account && cp.Name == cloudSpaceName {
+			return s.Get(cp.ID)
+		}
+	}
+	return nil, fmt.Errorf("cloudspace %s of account %s: %w", cloudSpaceName, account, ovc.ErrNotFound)
+}
+
+// Create a new cloudspace
+func (s *cloudSpaceService) Create(cloudSpaceConfig *ovc.CloudSpaceConfig) (int, error) {
+	body, err := s.api.post("/cloudapi/cloudspaces/create", *cloudSpaceConfig, ovc.OperationalActionTimeout)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(string(body))
+}
+
+// Update an existing cloudspace
+func (s *cloudSpaceService) Update(cloudSpaceConfig *ovc.CloudSpaceConfig) error {
+	_, err := s.api.post("/cloudapi/cloudspaces/update", *cloudSpaceConfig, ovc.ModelActionTimeout)
+	return err
+}
+
+// Delete a cloudspace
+func (s *cloudSpaceService) Delete(cloudSpaceConfig *ovc.CloudSpaceDeleteConfig) error {
+	_, err := s.api.post("/cloudapi/cloudspaces/delete", *cloudSpaceConfig, ovc.OperationalActionTimeout)
+	return err
+}
+
+// SetDefaultGateway sets default gateway of the cloudspace to the given IP address
+func (s *cloudSpaceService) SetDefaultGateway(id int, gateway string) error {
+	in := map[string]interface{}{"cloudspaceId": id, "gateway": gateway}
+	_, err := s.api.post("/cloudapi/cloudspaces/setDefaultGateway", in, ovc.OperationalActionTimeout)
+	return err
+}
+
+type accountService struct {
+	api *apiClient
+}
+
+// GetIDByName returns the account ID based on the account name
+func (s *accountService) GetIDByName(account string) (int, error) {
+	accounts, err := s.List()
+	if err != nil {
+		return 0, err
+	}
+	for _, acc := range *accounts {
+		if acc.Name == account {
+			return acc.ID, nil
+		}
+	}
+	return -1, fmt.Errorf("account %s: %w", account, ovc.ErrNotFound)
+}
+
+// List all accounts
+func (s *accountService) List() (*[]ovc.AccountInfo, error) {
+	accounts := new([]ovc.AccountInfo)
+	if err := s.api.postCached(accountsListEndpoint, nil, ovc.ModelActionTimeout, accounts); err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+type diskService struct {
+	ovc.DiskService
+	api *apiClient
+}
+
+// List all disks of an account, optionally of a given type
+func (s *diskService) List(accountID int, diskType string) (*[]ovc.Disk, error) {
+	in := map[string]interface{}{"accountId": accountID}
+	if len(diskType) != 0 {
+		in["type"] = diskType
+	}
+	disks := new([]ovc.Disk)
+	if err := s.api.postInto("/cloudapi/disks/list", in, ovc.OperationalActionTimeout, disks); err != nil {
+		return nil, err
+	}
+	return disks, nil
+}
+
+// diskEntry is a disk as returned by disks/list, including the machine it is attached to
+type diskEntry struct {
+	ovc.Disk
+	MachineID   int    `json:"machineId"`
+	MachineName string `json:"machineName"`
+}
+
+// listEntries lists the disks of an account like List, keeping their attachment
+func (s *diskService) listEntries(accountID int, diskType string) ([]diskEntry, error) {
+	in := map[string]interface{}{"accountId": accountID}
+	if len(diskType) != 0 {
+		in["type"] = diskType
+	}
+	var disks []diskEntry
+	if err := s.api.postInto("/cloudapi/disks/list", in, ovc.OperationalActionTimeout, &disks); err != nil {
+		return nil, err
+	}
+	return disks, nil
+}
+
+// Get individual disk
+func (s *diskService) Get(id int) (*ovc.DiskInfo, error) {
+	diskInfo := new(ovc.DiskInfo)
+	in := map[string]interface{}{"diskId": id}
+	if err := s.api.postInto("/cloudapi/disks/get", in, ovc.ModelActionTimeout, diskInfo); err != nil {
+		return nil, err
+	}
+	return diskInfo, nil
+}
+
+// GetByName gets a disk by its name
+func (s *diskService) GetByName(name string, accountID int, diskType string) (*ovc.DiskInfo, error) {
+	disks, err := s.List(accountID, diskType)
+	if err != nil {
+		return nil, err
+	}
+	for _, disk := range *disks {
+		if disk.Name == name {
+			return s.Get(disk.ID)
+		}
+	}
+	return nil, fmt.Errorf("disk %s: %w", name, ovc.ErrNotFound)
+}
+
+// CreateAndAttach creates a new disk and attaches it to a machine
+func (s *diskService) CreateAndAttach(diskConfig *ovc.DiskConfig) (int, error) {
+	body, err := s.api.post("/cloudapi/machines/addDisk", *diskConfig, ovc.OperationalActionTimeout)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(string(body))
+}
+
+// Update resizes and/or limits the IO of an existing disk
+func (s *diskService) Update(diskConfig *ovc.DiskConfig) error {
+	if diskConfig.Size != 0 {
+		if err := s.Resize(diskConfig); err != nil {
+			return err
+		}
+	}
+	if diskConfig.Size != 0 || diskConfig.IOPS != 0 {
+		_, err := s.api.post("/cloudapi/disks/limitIO", *diskConfig, ovc.OperationalActionTimeout)
+		return err
+	}
+	return nil
+}
+
+// Resize resizes a disk. Can only increase the size of a disk
+func (s *diskService) Resize(diskConfig *ovc.DiskConfig) error {
+	_, err := s.api.post("/cloudapi/disks/resize", *diskConfig, ovc.OperationalActionTimeout)
+	return err
+}
+
+// Delete an existing disk
+func (s *diskService) Delete(diskConfig *ovc.DiskDeleteConfig) error {
+	_, err := s.api.post("/cloudapi/disks/delete", *diskConfig, ovc.OperationalActionTimeout)
+	return err
+}
+
+type forwardingService struct {
+	ovc.ForwardingService
+	api *apiClient
+}
+
+// Create a new port forward, a random public port is picked if none is given
+func (s *forwardingService) Create(portForwardingConfig *ovc.PortForwardingConfig) (int, error) {
+	if portForwardingConfig.PublicPort == 0 {
+		publicPort, err := s.getRandomPublicPort(portForwardingConfig)
+		if err != nil {
+			return 0, err
+		}
+		portForwardingConfig.PublicPort = publicPort
+	}
+	_, err := s.api.post("/cloudapi/portforwarding/create", *portForwardingConfig, ovc.OperationalActionTimeout)
+	if err != nil {
+		return 0, err
+	}
+	return portForwardingConfig.PublicPort, nil
+}
+
+// Update an existing port forward
+func (s *forwardingService) Update(portForwardingConfig *ovc.PortForwardingConfig) error {
+	_, err := s.api.post("/cloudapi/portforwarding/updateByPort", *portForwardingConfig, ovc.OperationalActionTimeout)
+	return err
+}
+
+// Delete an existing port forward
+func (s *forwardingService) Delete(portForwardingConfig *ovc.PortForwardingConfig) error {
+	_, err := s.api.post("/cloudapi/portforwarding/deleteByPort", *portForwardingConfig, ovc.OperationalActionTimeout)
+	return err
+}
+
+// List all port forwards
+func (s *forwardingService) List(portForwardingConfig *ovc.PortForwardingConfig) (*[]ovc.PortForwardingInfo, error) {
+	portForwardingList := new([]ovc.PortForwardingInfo)
+	if err := s.api.postCached(portforwardsListEndpoint, *portForwardingConfig, ovc.ModelActionTimeout, portForwardingList); err != nil {
+		return nil, err
+	}
+	return portForwardingList, nil
+}
+
+func (s *forwardingService) getRandomPublicPort(portForwardingConfig *ovc.PortForwardingConfig) (int, error) {
+	// ports taken outside of this run must be seen, so the list is fetched again
+	s.api.invalidate(portforwardsListEndpoint)
+	list, err := s.List(&ovc.PortForwardingConfig{CloudspaceID: portForwardingConfig.CloudspaceID})
+	if err != nil {
+		return 0, err
+	}
+	used := make(map[string]bool, len(*list))
+	for _, port := range *list {
+		used[port.PublicPort] = true
+	}
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	publicPort := r.Intn(40000) + 2000
+	for used[strconv.Itoa(publicPort)] {
+		publicPort = r.Intn(40000) + 2000
+	}
+	return publicPort, nil
+}
+
+type templateService struct {
+	api *apiClient
+}
+
+// List all templates
+func (s *templateService) List(accountID int) (*[]ovc.Template, error) {
+	templates := new([]ovc.Template)
+	in := map[string]interface{}{"accountId": accountID}
+	if err := s.api.postCached(imagesListEndpoint, in, ovc.ModelActionTimeout, templates); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+type sizesService struct {
+	api *apiClient
+}
+
+// List all sizes
+func (s *sizesService) List(cloudspaceID int) (*[]ovc.Size, error) {
+	sizes := new([]ovc.Size)
+	in := map[string]interface{}{"cloudspaceId": cloudspaceID}
+	if err := s.api.postCached(sizesListEndpoint, in, ovc.ModelActionTimeout, sizes); err != nil {
+		return nil, err
+	}
+	return sizes, nil
+}
+
+// GetByVcpusAndMemory gets sizes by vcpus and memory
+func (s *sizesService) GetByVcpusAndMemory(vcpus int, memory int, cloudspaceID int) (*ovc.Size, error) {
+	sizes, err := s.List(cloudspaceID)
+	if err != nil {
+		return nil, err
+	}
+	for _, sz := range *sizes {
+		if sz.Vcpus == vcpus && sz.Memory == memory {
+			size := sz
+			return &size, nil
+		}
+	}
+	return nil, fmt.Errorf("size with %d vcpus and %d MB memory: %w", vcpus, memory, ovc.ErrNotFound)
+}
+
+type imageService struct {
+	ovc.ImageService
+	api *apiClient
+}
+
+// List all images available to an account
+func (s *imageService) List(accountID int) (*[]ovc.ImageInfo, error) {
+	images := new([]ovc.ImageInfo)
+	in := map[string]interface{}{"accountId": accountID}
+	if err := s.api.postCached(imagesListEndpoint, in, ovc.ModelActionTimeout, images); err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+type ipsecService struct {
+	api *apiClient
+}
+
+// Create a new ipsec tunnel and return its pre-shared key
+func (s *ipsecService) Create(ipsecConfig *ovc.IpsecConfig) (string, error) {
+	var psk string
+	if err := s.api.postInto("/cloudapi/ipsec/addTunnelToCloudspace", *ipsecConfig, ovc.OperationalActionTimeout, &psk); err != nil {
+		return "", err
+	}
+	return psk, nil
+}
+
+// Delete an existing ipsec tunnel
+func (s *ipsecService) Delete(ipsecConfig *ovc.IpsecConfig) error {
+	_, err := s.api.post("/cloudapi/ipsec/removeTunnelFromCloudspace", *ipsecConfig, ovc.OperationalActionTimeout)
+	return err
+}
+
+// List all ipsec tunnels of a cloudspace
+func (s *ipsecService) List(ipsecConfig *ovc.IpsecConfig) (*[]ovc.IpsecInfo, error) {
+	ipsecList := new([]ovc.IpsecInfo)
+	if err := s.api.postInto("/cloudapi/ipsec/listTunnels", *ipsecConfig, ovc.ModelActionTimeout, ipsecList); err != nil {
+		return nil, err
+	}
+	return ipsecList, nil
+}
+
+type externalNetworkService struct {
+	ovc.ExternalNetworkService
+	api *apiClient
+}
+
+// List all external networks available to an account
+func (s *externalNetworkService) List(accountID int) (*[]ovc.ExternalNetworkInfo, error) {
+	externalNetworks := new([]ovc.ExternalNetworkInfo)
+	in := map[string]interface{}{"accountId": accountID}
+	if err := s.api.postCached(externalNetworksListEndpoint, in, ovc.ModelActionTimeout, externalNetworks); err != nil {
+		return nil, err
+	}
+	return externalNetworks, nil
+}
+
+type locationService struct {
+	api *apiClient
+}
+
+// List lists all locations of the G8
+func (s *locationService) List() (*ovc.LocationList, error) {
+	locations := new(ovc.LocationList)
+	if err := s.api.postInto("/cloudapi/locations/list", nil, ovc.ModelActionTimeout, locations); err != nil {
+		return nil, err
+	}
+	return locations, nil
+}
@@ -0,0 +1,273 @@
+package ovc
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gig-tech/ovc-sdk-go/v3/ovc"
+)
+
+const (
+	identityProviderIYO  = "itsyouonline"
+	identityProviderOIDC = "oidc"
+
+	iyoTokenURL = "https://itsyou.online/v1/oauth/access_token"
+
+	// tokens are renewed when they expire within this buffer
+	tokenExpiryBuffer = 5 * time.Minute
+)
+
+// tokenRequestTimeout bounds a request to the token endpoint of the identity provider
+var tokenRequestTimeout = 30 * time.Second
+
+// authConfig describes how the provider obtains the JWT for the G8 API
+type authConfig struct {
+	identityProvider string
+	tokenURL         string
+	scopes           []string
+	usernameClaim    string
+	clientID         string
+	clientSecret     string
+	jwt              string
+}
+
+// tokenSource provides the JWT to authenticate a G8 API call with
+type tokenSource interface {
+	token() (string, error)
+	// refresh discards the current JWT after the G8 rejected it
+	refresh() error
+}
+
+// iyoTokenSource uses the JWT of the SDK, which refreshes itself at itsyou.online when possible
+type iyoTokenSource struct {
+	cfg        authConfig
+	httpClient *http.Client
+	logger     ovc.Logger
+
+	mu  sync.Mutex
+	jwt *ovc.JWT
+}
+
+func (s *iyoTokenSource) token() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.jwt.Get()
+}
+
+// refresh issues a new JWT with the client credentials. A JWT given without them can not be
+// issued again, so the call fails instead of being retried with the rejected JWT.
+func (s *iyoTokenSource) refresh() error {
+	if s.cfg.clientID == "" {
+		return fmt.Errorf("%w: renew client_jwt or configure client_id and client_secret", ovc.ErrExpiredJWT)
+	}
+	tokenString, _, err := fetchToken(s.httpClient, s.cfg)
+	if err != nil {
+		return err
+	}
+	jwt, err := ovc.NewJWTFromIYO(tokenString, s.logger)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jwt = jwt
+	return nil
+}
+
+// newTokenSource returns a token source for the configured identity provider
+// and the G8 access name of the authenticated user
+func newTokenSource(cfg authConfig, httpClient *http.Client, logger ovc.Logger) (tokenSource, string, error) {
+	if cfg.jwt == "" && (cfg.clientID == "" || cfg.clientSecret == "") {
+		return nil, "", fmt.Errorf("no credentials were provided")
+	}
+
+	switch cfg.identityProvider {
+	case identityProviderIYO:
+		tokenString := cfg.jwt
+		if tokenString == "" {
+			var err error
+			if tokenString, _, err = fetchToken(httpClient, cfg); err != nil {
+				return nil, "", err
+			}
+		}
+		// the SDK verifies the signature of the JWT and refreshes it at itsyou.online
+		jwt, err := ovc.NewJWTFromIYO(tokenString, logger)
+		if err != nil {
+			return nil, "", err
+		}
+		username, err := jwt.Claim(cfg.usernameClaim)
+		if err != nil {
+			return nil, "", fmt.Errorf("claim %q is not present in the JWT", cfg.usernameClaim)
+		}
+		return &iyoTokenSource{cfg: cfg, httpClient: httpClient, logger: logger, jwt: jwt}, fmt.Sprintf("%v@itsyouonline", username), nil
+
+	case identityProviderOIDC:
+		if cfg.jwt == "" && cfg.tokenURL == "" {
+			return nil, "", fmt.Errorf("token_url is required to authenticate with the oidc identity provider")
+		}
+		src := &oidcTokenSource{cfg: cfg, httpClient: httpClient}
+		if cfg.jwt != "" {
+			if err := src.set(cfg.jwt); err != nil {
+				return nil, "", err
+			}
+		} else if _, err := src.token(); err != nil {
+			return nil, "", err
+		}
+		username, ok := src.claims[cfg.usernameClaim].(string)
+		if !ok || username == "" {
+			return nil, "", fmt.Errorf("claim %q is not present in the JWT", cfg.usernameClaim)
+		}
+		return src, username, nil
+	}
+	return nil, "", fmt.Errorf("unsupported identity provider %q", cfg.identityProvider)
+}
+
+// oidcTokenSource fetches JWTs with the client credentials grant and renews them before they expire
+type oidcTokenSource struct {
+	cfg        authConfig
+	httpClient *http.Client
+
+	mu     sync.Mutex
+	raw    string
+	claims map[string]interface{}
+	expiry time.Time
+}
+
+func (s *oidcTokenSource) token() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.raw != "" && (s.expiry.IsZero() || time.Until(s.expiry) > tokenExpiryBuffer) {
+		return s.raw, nil
+	}
+	if s.cfg.clientID == "" {
+		if s.raw == "" {
+			return "", fmt.Errorf("no credentials were provided")
+		}
+		if time.Now().Before(s.expiry) {
+			// a JWT that can not be renewed is used until it has expired
+			return s.raw, nil
+		}
+		return "", ovc.ErrExpiredJWT
+	}
+	raw, expiresIn, err := fetchToken(s.httpClient, s.cfg)
+	if err != nil {
+		return "", err
+	}
+	if err := s.set(raw); err != nil {
+		return "", err
+	}
+	if s.expiry.IsZero() && expiresIn > 0 {
+		s.expiry = time.Now().Add(expiresIn)
+	}
+	return s.raw, nil
+}
+
+func (s *oidcTokenSource) refresh() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cfg.clientID == "" {
+		return ovc.ErrExpiredJWT
+	}
+	s.raw = ""
+	return nil
+}
+
+func (s *oidcTokenSource) set(raw string) error {
+	claims, err := jwtClaims(raw)
+	if err != nil {
+		return err
+	}
+	s.raw = raw
+	s.claims = claims
+	s.expiry = time.Time{}
+	if exp, ok := claims["exp"].(float64); ok {
+		s.expiry = time.Unix(int64(exp), 0)
+	}
+	return nil
+}
+
+// fetchToken requests a JWT from the token endpoint of the identity provider with the client credentials grant.
+// itsyou.online returns the JWT as response body, OAuth2/OIDC issuers return it in a JSON document.
+func fetchToken(httpClient *http.Client, cfg authConfig) (string, time.Duration, error) {
+	tokenURL := cfg.tokenURL
+	if tokenURL == "" {
+		tokenURL = iyoTokenURL
+	}
+	form := url.Values{}
+	form.Add("grant_type", "client_credentials")
+	form.Add("client_id", cfg.clientID)
+	form.Add("client_secret", cfg.clientSecret)
+	if cfg.identityProvider == identityProviderIYO {
+		form.Add("response_type", "id_token")
+		if len(cfg.scopes) > 0 {
+			form.Add("scope", strings.Join(cfg.scopes, ","))
+		}
+	} else if len(cfg.scopes) > 0 {
+		form.Add("scope", strings.Join(cfg.scopes, " "))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), tokenRequestTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("Error fetching JWT: %s", err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", 0, fmt.Errorf("Error reading JWT request body: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, fmt.Errorf("Failed to fetch JWT from %s: %s", tokenURL, strings.TrimSpace(string(body)))
+	}
+	if cfg.identityProvider == identityProviderIYO {
+		return strings.TrimSpace(string(body)), 0, nil
+	}
+
+	var tokenResp struct {
+		AccessToken string `json:"access_token"`
+		IDToken     string `json:"id_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &tokenResp); err != nil {
+		return "", 0, fmt.Errorf("Failed to parse token response of %s: %s", tokenURL, err)
+	}
+	token := tokenResp.AccessToken
+	if token == "" {
+		token = tokenResp.IDToken
+	}
+	if token == "" {
+		return "", 0, fmt.Errorf("token response of %s contains no access_token or id_token", tokenURL)
+	}
+	return token, time.Duration(tokenResp.ExpiresIn) * time.Second, nil
+}
+
+// jwtClaims decodes the claims of a JWT without verifying its signature, the G8 verifies the token
+func jwtClaims(token string) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ovc.ErrInvalidJWT
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return nil, ovc.ErrInvalidJWT
+	}
+	claims := make(map[string]interface{})
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, ovc.ErrInvalidJWT
+	}
+	return claims, nil
+}
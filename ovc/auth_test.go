@@ -0,0 +1,175 @@
+package ovc
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gig-tech/ovc-sdk-go/v3/ovc"
+)
+
+// unsignedJWT builds a JWT with the given claims, the provider does not verify signatures of oidc tokens
+func unsignedJWT(t *testing.T, claims map[string]interface{}) string {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`))
+	return header + "." + base64.RawURLEncoding.EncodeToString(payload) + ".sig"
+}
+
+func TestOIDCTokenSource(t *testing.T) {
+	requests := 0
+	// the first token expires within the renewal buffer
+	expiry := time.Now().Add(time.Minute)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		if got := r.PostForm.Get("grant_type"); got != "client_credentials" {
+			t.Errorf("grant_type = %q", got)
+		}
+		if r.PostForm.Get("client_id") != "id" || r.PostForm.Get("client_secret") != "secret" {
+			t.Errorf("unexpected client credentials %v", r.PostForm)
+		}
+		if got := r.PostForm.Get("scope"); got != "openid profile" {
+			t.Errorf("scope = %q", got)
+		}
+		token := unsignedJWT(t, map[string]interface{}{
+			"preferred_username": "jane",
+			"exp":                expiry.Unix(),
+		})
+		fmt.Fprintf(w, `{"access_token": %q, "token_type": "bearer", "expires_in": 3600}`, token)
+	}))
+	defer server.Close()
+
+	cfg := authConfig{
+		identityProvider: identityProviderOIDC,
+		tokenURL:         server.URL,
+		scopes:           []string{"openid", "profile"},
+		usernameClaim:    "preferred_username",
+		clientID:         "id",
+		clientSecret:     "secret",
+	}
+	tokens, access, err := newTokenSource(cfg, server.Client(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if access != "jane" {
+		t.Errorf("access = %q, want jane", access)
+	}
+	if requests != 1 {
+		t.Errorf("token endpoint called %d times, want 1", requests)
+	}
+
+	expiry = time.Now().Add(time.Hour)
+	for i := 0; i < 2; i++ {
+		if _, err := tokens.token(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if requests != 2 {
+		t.Errorf("token endpoint called %d times, want 2", requests)
+	}
+}
+
+func TestOIDCTokenSourceMissingClaim(t *testing.T) {
+	cfg := authConfig{
+		identityProvider: identityProviderOIDC,
+		usernameClaim:    "preferred_username",
+		jwt:              unsignedJWT(t, map[string]interface{}{"sub": "1234"}),
+	}
+	if _, _, err := newTokenSource(cfg, http.DefaultClient, nil); err == nil {
+		t.Error("expected an error for a JWT without the username claim")
+	}
+}
+
+func TestFetchTokenIYO(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		if got := r.PostForm.Get("response_type"); got != "id_token" {
+			t.Errorf("response_type = %q", got)
+		}
+		if got := r.PostForm.Get("scope"); got != "offline_access,user:name" {
+			t.Errorf("scope = %q", got)
+		}
+		fmt.Fprint(w, "header.payload.signature\n")
+	}))
+	defer server.Close()
+
+	cfg := authConfig{
+		identityProvider: identityProviderIYO,
+		tokenURL:         server.URL,
+		scopes:           []string{"offline_access", "user:name"},
+		clientID:         "id",
+		clientSecret:     "secret",
+	}
+	token, _, err := fetchToken(server.Client(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "header.payload.signature" {
+		t.Errorf("token = %q", token)
+	}
+}
+
+func TestFetchTokenTimesOut(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	defer func(timeout time.Duration) { tokenRequestTimeout = timeout }(tokenRequestTimeout)
+	tokenRequestTimeout = 50 * time.Millisecond
+
+	cfg := authConfig{identityProvider: identityProviderOIDC, tokenURL: server.URL, clientID: "id", clientSecret: "secret"}
+	done := make(chan error, 1)
+	go func() {
+		_, _, err := fetchToken(server.Client(), cfg)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("expected an error from a hanging token endpoint")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("fetchToken did not time out")
+	}
+}
+
+func TestIYORefreshReissuesOrFails(t *testing.T) {
+	src := &iyoTokenSource{cfg: authConfig{identityProvider: identityProviderIYO}}
+	if err := src.refresh(); !errors.Is(err, ovc.ErrExpiredJWT) || !strings.Contains(err.Error(), "client_jwt") {
+		t.Errorf("refresh of a client_jwt: %v", err)
+	}
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+	src = &iyoTokenSource{
+		cfg:        authConfig{identityProvider: identityProviderIYO, tokenURL: server.URL, clientID: "id", clientSecret: "secret"},
+		httpClient: server.Client(),
+	}
+	if err := src.refresh(); err == nil {
+		t.Error("expected the error of the token endpoint")
+	}
+	if requests != 1 {
+		t.Errorf("%d token requests, want a new JWT to be requested once", requests)
+	}
+}
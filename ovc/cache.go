@@ -0,0 +1,116 @@
+package ovc
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/gig-tech/ovc-sdk-go/v3/ovc"
+)
+
+// List endpoints of which the results are cached for the run of the provider
+const (
+	accountsListEndpoint         = "/cloudapi/accounts/list"
+	imagesListEndpoint           = "/cloudapi/images/list"
+	sizesListEndpoint            = "/cloudapi/sizes/list"
+	externalNetworksListEndpoint = "/cloudapi/externalnetwork/list"
+	machinesListEndpoint         = "/cloudapi/machines/list"
+	portforwardsListEndpoint     = "/cloudapi/portforwarding/list"
+)
+
+// readCache keeps the results of list calls by endpoint and arguments, so the many resources
+// and data sources of a configuration that look up the same list only fetch it once.
+// Concurrent calls for the same list wait for the first one instead of fetching it again.
+type readCache struct {
+	logger ovc.Logger
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+	hits    map[string]int
+	misses  map[string]int
+}
+
+type cacheEntry struct {
+	endpoint string
+	ready    chan struct{}
+	body     []byte
+	err      error
+}
+
+func newReadCache(logger ovc.Logger) *readCache {
+	return &readCache{
+		logger:  logger,
+		entries: make(map[string]*cacheEntry),
+		hits:    make(map[string]int),
+		misses:  make(map[string]int),
+	}
+}
+
+// get returns the cached result of the call, or fetches and caches it. Failed calls are not cached.
+func (c *readCache) get(endpoint string, in interface{}, fetch func() ([]byte, error)) ([]byte, error) {
+	args, err := json.Marshal(in)
+	if err != nil {
+		return nil, err
+	}
+	key := endpoint + " " + string(args)
+
+	c.mu.Lock()
+	if entry, ok := c.entries[key]; ok {
+		c.hits[endpoint]++
+		c.logStats(endpoint, true)
+		c.mu.Unlock()
+		<-entry.ready
+		if entry.err != nil {
+			// the error may be specific to the call that fetched the entry, like its context being canceled
+			return fetch()
+		}
+		return entry.body, nil
+	}
+	entry := &cacheEntry{endpoint: endpoint, ready: make(chan struct{})}
+	c.entries[key] = entry
+	c.misses[endpoint]++
+	c.logStats(endpoint, false)
+	c.mu.Unlock()
+
+	entry.body, entry.err = fetch()
+	close(entry.ready)
+	if entry.err != nil {
+		c.mu.Lock()
+		if c.entries[key] == entry {
+			delete(c.entries, key)
+		}
+		c.mu.Unlock()
+	}
+	return entry.body, entry.err
+}
+
+// invalidate drops the cached results of the given endpoints
+func (c *readCache) invalidate(endpoints ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, entry := range c.entries {
+		for _, endpoint := range endpoints {
+			if entry.endpoint == endpoint {
+				delete(c.entries, key)
+				break
+			}
+		}
+	}
+}
+
+// invalidateAll drops all cached results
+func (c *readCache) invalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*cacheEntry)
+}
+
+// logStats logs the hit rate of the endpoint, c.mu must be held
+func (c *readCache) logStats(endpoint string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	hits, misses := c.hits[endpoint], c.misses[endpoint]
+	c.logger.Debugf("OVC cache %s for %s: %d hits, %d misses (%.0f%% hit rate)",
+		result, endpoint, hits, misses, 100*float64(hits)/float64(hits+misses))
+}
@@ -0,0 +1,128 @@
+package ovc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gig-tech/ovc-sdk-go/v3/ovc"
+	"github.com/sirupsen/logrus"
+)
+
+func TestReadCache(t *testing.T) {
+	cache := newReadCache(ovc.LogrusAdapter{FieldLogger: logrus.New()})
+	fetches := 0
+	fetch := func() ([]byte, error) {
+		fetches++
+		return []byte("[]"), nil
+	}
+	args := map[string]interface{}{"cloudspaceId": 1}
+
+	for i := 0; i < 3; i++ {
+		if _, err := cache.get(machinesListEndpoint, args, fetch); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if fetches != 1 {
+		t.Errorf("fetches = %d, want 1", fetches)
+	}
+
+	// other arguments are another entry
+	cache.get(machinesListEndpoint, map[string]interface{}{"cloudspaceId": 2}, fetch)
+	if fetches != 2 {
+		t.Errorf("fetches = %d, want 2", fetches)
+	}
+
+	cache.invalidate(imagesListEndpoint)
+	cache.get(machinesListEndpoint, args, fetch)
+	if fetches != 2 {
+		t.Errorf("fetches = %d after invalidating another endpoint, want 2", fetches)
+	}
+
+	cache.invalidate(machinesListEndpoint)
+	cache.get(machinesListEndpoint, args, fetch)
+	if fetches != 3 {
+		t.Errorf("fetches = %d after invalidating, want 3", fetches)
+	}
+}
+
+func TestReadCacheSkipsErrors(t *testing.T) {
+	cache := newReadCache(ovc.LogrusAdapter{FieldLogger: logrus.New()})
+	fetches := 0
+	fetch := func() ([]byte, error) {
+		fetches++
+		return nil, errors.New("unavailable")
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := cache.get(accountsListEndpoint, nil, fetch); err == nil {
+			t.Fatal("expected an error")
+		}
+	}
+	if fetches != 2 {
+		t.Errorf("fetches = %d, want 2", fetches)
+	}
+}
+
+func TestWritesInvalidateTheCache(t *testing.T) {
+	var mu sync.Mutex
+	calls := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == taskEndpoint {
+			// the task GUID is the endpoint that started it
+			var task struct {
+				GUID string `json:"taskguid"`
+			}
+			json.NewDecoder(r.Body).Decode(&task)
+			if task.GUID == "/cloudapi/machines/get" {
+				fmt.Fprint(w, `[true, {"id": 1, "name": "vm"}]`)
+				return
+			}
+			fmt.Fprint(w, `[true, [{"id": 1, "name": "vm"}]]`)
+			return
+		}
+		mu.Lock()
+		calls[r.URL.Path]++
+		mu.Unlock()
+		fmt.Fprintf(w, "%q", r.URL.Path)
+	}))
+	defer server.Close()
+
+	api := newTestAPIClient(t, context.Background(), server, retryPolicy{})
+	machines := &machineService{api: api}
+	disks := &diskService{api: api}
+	listCalls := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return calls[machinesListEndpoint]
+	}
+	writes := map[string]func() error{
+		"update":   func() error { _, err := machines.Update(&ovc.MachineConfig{MachineID: "1"}); return err },
+		"resize":   func() error { _, err := machines.Resize(&ovc.MachineConfig{MachineID: "1"}); return err },
+		"add disk": func() error { _, err := disks.CreateAndAttach(&ovc.DiskConfig{MachineID: 1}); return err },
+		"limit IO": func() error { return disks.Update(&ovc.DiskConfig{DiskID: 2, IOPS: 500}) },
+	}
+	for name, write := range writes {
+		if _, err := machines.List(1); err != nil {
+			t.Fatal(err)
+		}
+		before := listCalls()
+		// results are served from the cache until a write
+		machines.List(1)
+		if listCalls() != before {
+			t.Fatalf("%s: the machine list was fetched again before the write", name)
+		}
+		write()
+		// GetByName looks the machine up in the list
+		if _, err := machines.GetByName("vm", 1); err != nil {
+			t.Fatal(err)
+		}
+		if listCalls() != before+1 {
+			t.Errorf("%s: the machine list was served from the cache after the write", name)
+		}
+	}
+}
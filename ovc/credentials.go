@@ -0,0 +1,148 @@
+package ovc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gig-tech/ovc-sdk-go/v3/ovc"
+)
+
+// credentialLoader returns the current credentials of an external source,
+// only the jwt, clientID and clientSecret fields of the config are used
+type credentialLoader func() (authConfig, error)
+
+// jwtFileLoader reads the JWT from a file that can be renewed while the provider runs
+func jwtFileLoader(path string) credentialLoader {
+	return func() (authConfig, error) {
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return authConfig{}, fmt.Errorf("error reading client_jwt_file: %v", err)
+		}
+		jwt := strings.TrimSpace(string(content))
+		if jwt == "" {
+			return authConfig{}, fmt.Errorf("client_jwt_file %s is empty", path)
+		}
+		return authConfig{jwt: jwt}, nil
+	}
+}
+
+// processLoader runs a command that prints a JWT, or a JSON document with
+// either a client_jwt or a client_id and client_secret to stdout
+func processLoader(command string) credentialLoader {
+	return func() (authConfig, error) {
+		var stdout, stderr bytes.Buffer
+		cmd := exec.Command("sh", "-c", command)
+		cmd.Stdout = &stdout
+		cmd.Stderr = &stderr
+		if err := cmd.Run(); err != nil {
+			return authConfig{}, fmt.Errorf("credential_process failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		output := strings.TrimSpace(stdout.String())
+		if !strings.HasPrefix(output, "{") {
+			if output == "" {
+				return authConfig{}, fmt.Errorf("credential_process returned no credentials")
+			}
+			return authConfig{jwt: output}, nil
+		}
+		var creds struct {
+			ClientJWT    string `json:"client_jwt"`
+			ClientID     string `json:"client_id"`
+			ClientSecret string `json:"client_secret"`
+		}
+		if err := json.Unmarshal([]byte(output), &creds); err != nil {
+			return authConfig{}, fmt.Errorf("credential_process returned invalid JSON: %v", err)
+		}
+		return authConfig{jwt: creds.ClientJWT, clientID: creds.ClientID, clientSecret: creds.ClientSecret}, nil
+	}
+}
+
+// credentialReloadInterval is the minimum time between reloads of credentials that are about to expire,
+// so an external source that has not renewed its JWT yet is not run for every API call
+const credentialReloadInterval = 30 * time.Second
+
+// reloadingTokenSource loads the credentials again when the JWT is rejected by the G8 or is about to expire
+type reloadingTokenSource struct {
+	cfg        authConfig
+	load       credentialLoader
+	httpClient *http.Client
+	logger     ovc.Logger
+
+	mu         sync.Mutex
+	src        tokenSource
+	access     string
+	lastReload time.Time
+}
+
+// newReloadingTokenSource loads the initial credentials and returns the token source with the G8 access name
+func newReloadingTokenSource(cfg authConfig, load credentialLoader, httpClient *http.Client, logger ovc.Logger) (tokenSource, string, error) {
+	s := &reloadingTokenSource{
+		cfg:        cfg,
+		load:       load,
+		httpClient: httpClient,
+		logger:     logger,
+	}
+	if err := s.reload(); err != nil {
+		return nil, "", err
+	}
+	return s, s.access, nil
+}
+
+func (s *reloadingTokenSource) reload() error {
+	s.lastReload = time.Now()
+	creds, err := s.load()
+	if err != nil {
+		return err
+	}
+	cfg := s.cfg
+	cfg.jwt, cfg.clientID, cfg.clientSecret = creds.jwt, creds.clientID, creds.clientSecret
+	src, access, err := newTokenSource(cfg, s.httpClient, s.logger)
+	if err != nil {
+		return err
+	}
+	if s.access != "" && access != s.access {
+		return fmt.Errorf("reloaded credentials belong to %s instead of %s", access, s.access)
+	}
+	s.src, s.access = src, access
+	return nil
+}
+
+func (s *reloadingTokenSource) token() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	token, err := s.src.token()
+	if err == nil && !expiresSoon(token) {
+		return token, nil
+	}
+	if time.Since(s.lastReload) < credentialReloadInterval {
+		// the credentials were reloaded recently, the JWT is used as long as the G8 accepts it
+		return token, err
+	}
+	if err := s.reload(); err != nil {
+		return "", err
+	}
+	// the source may not have renewed the JWT yet, it is used as long as the G8 accepts it
+	return s.src.token()
+}
+
+func (s *reloadingTokenSource) refresh() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.reload()
+}
+
+// expiresSoon reports whether the JWT expires within the renewal buffer
+func expiresSoon(token string) bool {
+	claims, err := jwtClaims(token)
+	if err != nil {
+		return false
+	}
+	exp, ok := claims["exp"].(float64)
+	return ok && time.Until(time.Unix(int64(exp), 0)) <= tokenExpiryBuffer
+}
@@ -0,0 +1,114 @@
+package ovc
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gig-tech/ovc-sdk-go/v3/ovc"
+	"github.com/sirupsen/logrus"
+)
+
+func TestJWTFileIsReadAgainOnUnauthorized(t *testing.T) {
+	oldJWT := unsignedJWT(t, map[string]interface{}{"preferred_username": "ci", "exp": time.Now().Add(time.Hour).Unix()})
+	newJWT := unsignedJWT(t, map[string]interface{}{"preferred_username": "ci", "exp": time.Now().Add(2 * time.Hour).Unix()})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "bearer "+newJWT {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path == taskEndpoint {
+			fmt.Fprint(w, `[true, {"id": 1}]`)
+			return
+		}
+		fmt.Fprint(w, `"task-guid"`)
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "ovc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "jwt")
+	if err := ioutil.WriteFile(path, []byte(oldJWT+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := authConfig{identityProvider: identityProviderOIDC, usernameClaim: "preferred_username"}
+	tokens, access, err := newReloadingTokenSource(cfg, jwtFileLoader(path), server.Client(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if access != "ci" {
+		t.Errorf("access = %q, want ci", access)
+	}
+
+	// the sidecar renews the JWT while the provider runs
+	if err := ioutil.WriteFile(path, []byte(newJWT), 0600); err != nil {
+		t.Fatal(err)
+	}
+	logger := ovc.LogrusAdapter{FieldLogger: logrus.New()}
+	api := newAPIClient(context.Background(), server.URL, tokens, server.Client(), logger, apiOptions{concurrency: concurrencyLimits{model: 1}})
+	if _, err := api.post("/cloudapi/machines/get", nil, ovc.ModelActionTimeout); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestProcessLoader(t *testing.T) {
+	creds, err := processLoader(`echo '{"client_id": "id", "client_secret": "secret"}'`)()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if creds.clientID != "id" || creds.clientSecret != "secret" || creds.jwt != "" {
+		t.Errorf("unexpected credentials %+v", creds)
+	}
+
+	creds, err = processLoader("echo header.payload.signature")()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if creds.jwt != "header.payload.signature" {
+		t.Errorf("jwt = %q", creds.jwt)
+	}
+
+	if _, err := processLoader("exit 1")(); err == nil {
+		t.Error("expected an error for a failing credential_process")
+	}
+}
+
+func TestExpiringCredentialsAreNotReloadedPerCall(t *testing.T) {
+	loads := 0
+	jwt := unsignedJWT(t, map[string]interface{}{"preferred_username": "ci", "exp": time.Now().Add(time.Minute).Unix()})
+	load := func() (authConfig, error) {
+		loads++
+		return authConfig{jwt: jwt}, nil
+	}
+	cfg := authConfig{identityProvider: identityProviderOIDC, usernameClaim: "preferred_username"}
+	tokens, _, err := newReloadingTokenSource(cfg, load, http.DefaultClient, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		if _, err := tokens.token(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("credentials loaded %d times for a JWT that expires soon, want 1", loads)
+	}
+	// a JWT rejected by the G8 always reloads the credentials
+	if err := tokens.refresh(); err != nil {
+		t.Fatal(err)
+	}
+	if loads != 2 {
+		t.Errorf("credentials loaded %d times after refresh, want 2", loads)
+	}
+}
@@ -46,7 +46,7 @@ func dataSourceOvcDisk() *schema.Resource {
 }
 
 func dataSourceOvcDiskRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(*ovc.Client)
+	client := m.(*providerMeta).client
 	var err error
 	var accountID int
 	account := d.Get("account")
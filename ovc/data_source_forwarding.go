@@ -1,6 +1,7 @@
 package ovc
 
 import (
+	"fmt"
 	"strconv"
 
 	"github.com/gig-tech/ovc-sdk-go/v3/ovc"
@@ -53,39 +54,27 @@ func dataSourceOvcPortForwarding() *schema.Resource {
 }
 
 func dataSourceOvcPortForwardingRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(*ovc.Client)
-	var list *[]ovc.PortForwardingInfo
-	if machineID, ok := d.GetOk("machine_id"); ok {
-		if cloudspaceID, ok := d.GetOk("cloudspace_id"); ok {
-			cID, err := strconv.Atoi(cloudspaceID.(string))
-			if err != nil {
-				return err
-			}
-			mID, err := strconv.Atoi(machineID.(string))
-			if err != nil {
-				return err
-			}
-			portForwardingConfig := &ovc.PortForwardingConfig{
-				CloudspaceID: cID,
-				MachineID:    mID,
-			}
-			list, err = client.Portforwards.List(portForwardingConfig)
-			if err != nil {
-				return err
-			}
-		}
+	client := m.(*providerMeta).client
+	portForwardingConfig := &ovc.PortForwardingConfig{
+		CloudspaceID: d.Get("cloudspace_id").(int),
+		MachineID:    d.Get("machine_id").(int),
+	}
+	list, err := client.Portforwards.List(portForwardingConfig)
+	if err != nil {
+		return err
 	}
+	localPort := d.Get("local_port").(string)
 	for _, port := range *list {
-		if port.LocalPort == d.Get("local_port") {
+		if port.LocalPort == localPort {
 			d.SetId(strconv.Itoa(port.ID))
 			d.Set("protocol", port.Protocol)
 			d.Set("machine_name", port.MachineName)
 			d.Set("public_ip", port.PublicIP)
 			d.Set("local_ip", port.LocalIP)
 			d.Set("public_port", port.PublicPort)
-			d.Set("port_forward_id", port.ID)
+			d.Set("port_forward_id", strconv.Itoa(port.ID))
+			return nil
 		}
 	}
-	return nil
-
+	return fmt.Errorf("No port forward to local port %s of machine %d was found", localPort, portForwardingConfig.MachineID)
 }
@@ -0,0 +1,116 @@
+package ovc
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gig-tech/ovc-sdk-go/v3/ovc"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceOvcAccount() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceOvcAccountRead,
+
+		Schema: map[string]*schema.Schema{
+			"account_id": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"name"},
+			},
+			"name": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"account_id"},
+			},
+			"creation_time": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"update_time": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"acl": accountACLSchema(),
+		},
+	}
+}
+
+// accountACLSchema describes the access rights of the users of an account
+func accountACLSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"user_group_id": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"right": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"status": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"type": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"explicit": {
+					Type:     schema.TypeBool,
+					Computed: true,
+				},
+			},
+		},
+	}
+}
+
+func flattenAccountACL(acl []ovc.AccountACL) []map[string]interface{} {
+	result := make([]map[string]interface{}, len(acl))
+	for i, ace := range acl {
+		result[i] = map[string]interface{}{
+			"user_group_id": ace.UserGroupID,
+			"right":         ace.Right,
+			"status":        ace.Status,
+			"type":          ace.Type,
+			"explicit":      ace.Explicit,
+		}
+	}
+	return result
+}
+
+func dataSourceOvcAccountRead(d *schema.ResourceData, m interface{}) error {
+	name := d.Get("name").(string)
+	accountID := d.Get("account_id").(int)
+
+	if accountID == 0 && name == "" {
+		return fmt.Errorf("Either 'name' or 'account_id' should be given to define account datasource")
+	}
+
+	client := m.(*providerMeta).client
+	accounts, err := client.Accounts.List()
+	if err != nil {
+		return err
+	}
+
+	for _, account := range *accounts {
+		if (accountID != 0 && accountID == account.ID) || (name != "" && name == account.Name) {
+			d.SetId(strconv.Itoa(account.ID))
+			d.Set("account_id", account.ID)
+			d.Set("name", account.Name)
+			d.Set("creation_time", account.CreationTime)
+			d.Set("update_time", account.UpdateTime)
+			return d.Set("acl", flattenAccountACL(account.ACL))
+		}
+	}
+
+	if accountID != 0 {
+		return fmt.Errorf("No account with ID %d is accessible", accountID)
+	}
+	return fmt.Errorf("No account with name '%s' is accessible", name)
+}
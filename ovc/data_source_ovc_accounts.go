@@ -0,0 +1,81 @@
+package ovc
+
+import (
+	"regexp"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+func dataSourceOvcAccounts() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceOvcAccountsRead,
+
+		Schema: withFilters(map[string]*schema.Schema{
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.ValidateRegexp,
+			},
+			"entities": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"creation_time": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"update_time": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"acl": accountACLSchema(),
+					},
+				},
+			},
+		}),
+	}
+}
+
+func dataSourceOvcAccountsRead(d *schema.ResourceData, m interface{}) error {
+	client := m.(*providerMeta).client
+	accounts, err := client.Accounts.List()
+	if err != nil {
+		return err
+	}
+
+	re, err := regexp.Compile(d.Get("name_regex").(string))
+	if err != nil {
+		return err
+	}
+
+	entities := make([]map[string]interface{}, 0, len(*accounts))
+	for _, account := range *accounts {
+		if !re.MatchString(account.Name) {
+			continue
+		}
+		entity := make(map[string]interface{})
+		entity["id"] = account.ID
+		entity["name"] = account.Name
+		entity["creation_time"] = account.CreationTime
+		entity["update_time"] = account.UpdateTime
+		entity["acl"] = flattenAccountACL(account.ACL)
+		entities = append(entities, entity)
+	}
+
+	if err = d.Set("entities", applyFilters(d, entities)); err != nil {
+		return err
+	}
+
+	d.SetId(queryID(d, "name_regex"))
+	return nil
+}
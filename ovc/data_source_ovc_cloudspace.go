@@ -91,7 +91,7 @@ func dataSourceOvcCloudSpace() *schema.Resource {
 }
 
 func dataSourceOvcCloudSpaceRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(*ovc.Client)
+	client := m.(*providerMeta).client
 	var cloudSpace *ovc.CloudSpace
 	var err error
 	if v, ok := d.GetOk("cloudspace_id"); ok {
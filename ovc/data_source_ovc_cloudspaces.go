@@ -1,7 +1,6 @@
 package ovc
 
 import (
-	"github.com/gig-tech/ovc-sdk-go/v3/ovc"
 	"github.com/hashicorp/terraform/helper/schema"
 )
 
@@ -9,7 +8,7 @@ func dataSourceOvcCloudSpaces() *schema.Resource {
 	return &schema.Resource{
 		Read: dataSourceOvcCloudSpacesRead,
 
-		Schema: map[string]*schema.Schema{
+		Schema: withFilters(map[string]*schema.Schema{
 			"entities": {
 				Type:     schema.TypeList,
 				Computed: true,
@@ -60,12 +59,12 @@ func dataSourceOvcCloudSpaces() *schema.Resource {
 					},
 				},
 			},
-		},
+		}),
 	}
 }
 
 func dataSourceOvcCloudSpacesRead(d *schema.ResourceData, m interface{}) error {
-	c := m.(*ovc.Client)
+	c := m.(*providerMeta).client
 	cloudSpaces, err := c.CloudSpaces.List()
 	if err != nil {
 		return err
@@ -85,9 +84,9 @@ func dataSourceOvcCloudSpacesRead(d *schema.ResourceData, m interface{}) error {
 		entity["account"] = cp.AccountName
 		entities[i] = entity
 	}
-	if err := d.Set("entities", entities); err != nil {
+	if err := d.Set("entities", applyFilters(d, entities)); err != nil {
 		return err
 	}
-	d.SetId("1")
+	d.SetId(queryID(d))
 	return nil
 }
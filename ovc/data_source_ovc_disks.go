@@ -0,0 +1,123 @@
+package ovc
+
+import (
+	"regexp"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+func dataSourceOvcDisks() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceOvcDisksRead,
+
+		Schema: withFilters(map[string]*schema.Schema{
+			"account": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"B", "D", "C"}, false),
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.ValidateRegexp,
+			},
+			"entities": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"account_id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"description": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"size_max": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"machine_id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"machine_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		}),
+	}
+}
+
+func dataSourceOvcDisksRead(d *schema.ResourceData, m interface{}) error {
+	meta := m.(*providerMeta)
+	accountID, err := meta.client.Accounts.GetIDByName(d.Get("account").(string))
+	if err != nil {
+		return err
+	}
+
+	disks, err := (&diskService{api: meta.api}).listEntries(accountID, d.Get("type").(string))
+	if err != nil {
+		return err
+	}
+
+	re, err := regexp.Compile(d.Get("name_regex").(string))
+	if err != nil {
+		return err
+	}
+	status := d.Get("status").(string)
+
+	entities := make([]map[string]interface{}, 0, len(disks))
+	for _, disk := range disks {
+		if (status != "" && status != disk.Status) || !re.MatchString(disk.Name) {
+			continue
+		}
+		entity := make(map[string]interface{})
+		entity["id"] = disk.ID
+		entity["account_id"] = disk.AccountID
+		entity["name"] = disk.Name
+		entity["description"] = disk.Description
+		entity["type"] = disk.Type
+		entity["status"] = disk.Status
+		entity["size_max"] = disk.Size
+		entity["machine_id"] = disk.MachineID
+		entity["machine_name"] = disk.MachineName
+		entities = append(entities, entity)
+	}
+
+	if err = d.Set("entities", applyFilters(d, entities)); err != nil {
+		return err
+	}
+
+	d.SetId(queryID(d, "account", "type", "status", "name_regex"))
+	return nil
+}
@@ -51,7 +51,7 @@ func dataSourceOvcExternalNetworkRead(d *schema.ResourceData, m interface{}) err
 		return fmt.Errorf("Either 'name' or 'network_id' should be given to define external network datasource")
 	}
 
-	client := m.(*ovc.Client)
+	client := m.(*providerMeta).client
 
 	var err error
 	var accountID int
@@ -3,7 +3,6 @@ package ovc
 import (
 	"strconv"
 
-	"github.com/gig-tech/ovc-sdk-go/v3/ovc"
 	"github.com/hashicorp/terraform/helper/schema"
 )
 
@@ -11,7 +10,7 @@ func dataSourceOvcExternalNetworks() *schema.Resource {
 	return &schema.Resource{
 		Read: dataSourceOvcExternalNetworksRead,
 
-		Schema: map[string]*schema.Schema{
+		Schema: withFilters(map[string]*schema.Schema{
 			"account": {
 				Type:     schema.TypeString,
 				Optional: true,
@@ -56,12 +55,12 @@ func dataSourceOvcExternalNetworks() *schema.Resource {
 					},
 				},
 			},
-		},
+		}),
 	}
 }
 
 func dataSourceOvcExternalNetworksRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(*ovc.Client)
+	client := m.(*providerMeta).client
 	var err error
 	var accountID int
 	account := d.Get("account")
@@ -78,9 +77,9 @@ func dataSourceOvcExternalNetworksRead(d *schema.ResourceData, m interface{}) er
 
 	name := d.Get("name").(string)
 
-	entities := make([]map[string]interface{}, len(*externalNetworks))
+	entities := make([]map[string]interface{}, 0, len(*externalNetworks))
 
-	for i, externalNetwork := range *externalNetworks {
+	for _, externalNetwork := range *externalNetworks {
 		// select externalNetworks by name of network
 		if (name == "" || name == externalNetwork.Name) && (externalNetwork.AccountID == accountID || externalNetwork.AccountID == 0) {
 			entity := make(map[string]interface{})
@@ -91,14 +90,14 @@ func dataSourceOvcExternalNetworksRead(d *schema.ResourceData, m interface{}) er
 			entity["subnetmask"] = externalNetwork.Subnetmask
 			entity["dhcp"] = externalNetwork.DHCP
 			entity["account_id"] = externalNetwork.AccountID
-			entities[i] = entity
+			entities = append(entities, entity)
 		}
 	}
 
-	if err = d.Set("entities", entities); err != nil {
+	if err = d.Set("entities", applyFilters(d, entities)); err != nil {
 		return err
 	}
 
-	d.SetId("1")
+	d.SetId(queryID(d, "account", "name"))
 	return nil
 }
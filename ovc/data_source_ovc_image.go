@@ -8,6 +8,7 @@ import (
 
 	"github.com/gig-tech/ovc-sdk-go/v3/ovc"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 )
 
 func dataSourceOvcImage() *schema.Resource {
@@ -20,8 +21,9 @@ func dataSourceOvcImage() *schema.Resource {
 				Optional: true,
 			},
 			"name_regex": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.ValidateRegexp,
 			},
 			"most_recent": {
 				Type:     schema.TypeBool,
@@ -42,7 +44,7 @@ func dataSourceOvcImage() *schema.Resource {
 }
 
 func dataSourceOvcImageRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(*ovc.Client)
+	client := m.(*providerMeta).client
 	var err error
 	var accountID int
 	account := d.Get("account")
@@ -58,14 +60,16 @@ func dataSourceOvcImageRead(d *schema.ResourceData, m interface{}) error {
 		return err
 	}
 
-	nameRegex := d.Get("name_regex").(string)
-	re := regexp.MustCompile(nameRegex)
+	re, err := regexp.Compile(d.Get("name_regex").(string))
+	if err != nil {
+		return err
+	}
 
 	filteredImages := make([]ovc.ImageInfo, 0)
 
 	for _, image := range *images {
 		// select images by name
-		if nameRegex == "" || re.FindString(image.Name) != "" {
+		if re.MatchString(image.Name) {
 			filteredImages = append(filteredImages, image)
 		}
 	}
@@ -4,22 +4,23 @@ import (
 	"regexp"
 	"strconv"
 
-	"github.com/gig-tech/ovc-sdk-go/v3/ovc"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 )
 
 func dataSourceOvcImages() *schema.Resource {
 	return &schema.Resource{
 		Read: dataSourceOvcImagesRead,
 
-		Schema: map[string]*schema.Schema{
+		Schema: withFilters(map[string]*schema.Schema{
 			"account": {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
 			"name_regex": {
-				Type:     schema.TypeString,
-				Optional: true,
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.ValidateRegexp,
 			},
 			"entities": {
 				Type:     schema.TypeList,
@@ -41,12 +42,12 @@ func dataSourceOvcImages() *schema.Resource {
 					},
 				},
 			},
-		},
+		}),
 	}
 }
 
 func dataSourceOvcImagesRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(*ovc.Client)
+	client := m.(*providerMeta).client
 	var err error
 	var accountID int
 	account := d.Get("account")
@@ -62,14 +63,16 @@ func dataSourceOvcImagesRead(d *schema.ResourceData, m interface{}) error {
 		return err
 	}
 
-	nameRegex := d.Get("name_regex").(string)
-	re := regexp.MustCompile(nameRegex)
+	re, err := regexp.Compile(d.Get("name_regex").(string))
+	if err != nil {
+		return err
+	}
 
-	entities := make([]map[string]interface{}, len(*images))
+	entities := make([]map[string]interface{}, 0, len(*images))
 
-	for i, image := range *images {
+	for _, image := range *images {
 		// select images by name
-		if nameRegex == "" || re.FindString(image.Name) != "" {
+		if re.MatchString(image.Name) {
 			entity := make(map[string]interface{})
 			entity["id"] = strconv.Itoa(image.ID)
 			entity["name"] = image.Name
@@ -78,14 +81,14 @@ func dataSourceOvcImagesRead(d *schema.ResourceData, m interface{}) error {
 			} else {
 				entity["account"] = ""
 			}
-			entities[i] = entity
+			entities = append(entities, entity)
 		}
 	}
 
-	if err = d.Set("entities", entities); err != nil {
+	if err = d.Set("entities", applyFilters(d, entities)); err != nil {
 		return err
 	}
 
-	d.SetId("1")
+	d.SetId(queryID(d, "account", "name_regex"))
 	return nil
 }
@@ -0,0 +1,65 @@
+package ovc
+
+import (
+	"github.com/gig-tech/ovc-sdk-go/v3/ovc"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceOvcIpsecTunnels() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceOvcIpsecTunnelsRead,
+
+		Schema: withFilters(map[string]*schema.Schema{
+			"cloudspace_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+			},
+			"entities": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"remote_public_ip": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"remote_private_network": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"psk": {
+							Type:      schema.TypeString,
+							Computed:  true,
+							Sensitive: true,
+						},
+					},
+				},
+			},
+		}),
+	}
+}
+
+func dataSourceOvcIpsecTunnelsRead(d *schema.ResourceData, m interface{}) error {
+	client := m.(*providerMeta).client
+	cloudspaceID := d.Get("cloudspace_id").(int)
+	tunnels, err := client.Ipsec.List(&ovc.IpsecConfig{CloudspaceID: cloudspaceID})
+	if err != nil {
+		return err
+	}
+
+	entities := make([]map[string]interface{}, len(*tunnels))
+	for i, tunnel := range *tunnels {
+		entity := make(map[string]interface{})
+		entity["remote_public_ip"] = tunnel.RemoteAddr
+		entity["remote_private_network"] = tunnel.RemotePrivateNetwork
+		entity["psk"] = tunnel.PSK
+		entities[i] = entity
+	}
+
+	if err = d.Set("entities", applyFilters(d, entities)); err != nil {
+		return err
+	}
+
+	d.SetId(queryID(d, "cloudspace_id"))
+	return nil
+}
@@ -0,0 +1,68 @@
+package ovc
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceOvcLocation() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceOvcLocationRead,
+
+		Schema: map[string]*schema.Schema{
+			"code": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"name"},
+			},
+			"name": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"code"},
+			},
+			"grid_id": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"flag": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceOvcLocationRead(d *schema.ResourceData, m interface{}) error {
+	code := d.Get("code").(string)
+	name := d.Get("name").(string)
+
+	if code == "" && name == "" {
+		return fmt.Errorf("Either 'code' or 'name' should be given to define location datasource")
+	}
+
+	client := m.(*providerMeta).client
+	locations, err := client.Locations.List()
+	if err != nil {
+		return err
+	}
+
+	for _, location := range *locations {
+		if (code != "" && code == location.Code) || (name != "" && name == location.Name) {
+			d.SetId(strconv.Itoa(location.ID))
+			d.Set("code", location.Code)
+			d.Set("name", location.Name)
+			d.Set("grid_id", location.GridID)
+			d.Set("flag", location.Flag)
+			return nil
+		}
+	}
+
+	if code != "" {
+		return fmt.Errorf("No location with code '%s' was found", code)
+	}
+	return fmt.Errorf("No location with name '%s' was found", name)
+}
@@ -0,0 +1,68 @@
+package ovc
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func dataSourceOvcLocations() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceOvcLocationsRead,
+
+		Schema: withFilters(map[string]*schema.Schema{
+			"entities": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"code": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"grid_id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"flag": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		}),
+	}
+}
+
+func dataSourceOvcLocationsRead(d *schema.ResourceData, m interface{}) error {
+	client := m.(*providerMeta).client
+	locations, err := client.Locations.List()
+	if err != nil {
+		return err
+	}
+
+	entities := make([]map[string]interface{}, len(*locations))
+	for i, location := range *locations {
+		entity := make(map[string]interface{})
+		entity["id"] = location.ID
+		entity["name"] = location.Name
+		entity["code"] = location.Code
+		entity["grid_id"] = location.GridID
+		entity["flag"] = location.Flag
+		entities[i] = entity
+	}
+
+	if err = d.Set("entities", applyFilters(d, entities)); err != nil {
+		return err
+	}
+
+	d.SetId(queryID(d))
+	return nil
+}
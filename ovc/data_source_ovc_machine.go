@@ -1,6 +1,7 @@
 package ovc
 
 import (
+	"fmt"
 	"strconv"
 
 	"github.com/gig-tech/ovc-sdk-go/v3/ovc"
@@ -13,16 +14,27 @@ func dataSourceOvcMachine() *schema.Resource {
 
 		Schema: map[string]*schema.Schema{
 			"machine_id": {
-				Type:     schema.TypeInt,
-				Computed: true,
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"name", "reference_id"},
+			},
+			"reference_id": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"machine_id", "name"},
 			},
 			"cloudspace_id": {
 				Type:     schema.TypeInt,
-				Required: true,
+				Optional: true,
+				Computed: true,
 			},
 			"name": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"machine_id", "reference_id"},
 			},
 			"description": {
 				Type:     schema.TypeString,
@@ -64,6 +76,47 @@ func dataSourceOvcMachine() *schema.Resource {
 				Type:     schema.TypeBool,
 				Computed: true,
 			},
+			"vcpus": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"memory": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"storage": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"disks": machineDisksSchema(),
+			"acl": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"user_group_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"right": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"can_be_deleted": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+					},
+				},
+			},
 			"accounts": {
 				Type:     schema.TypeList,
 				Computed: true,
@@ -125,20 +178,24 @@ func dataSourceOvcMachine() *schema.Resource {
 }
 
 func dataSourceOvcMachineRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(*ovc.Client)
+	client := m.(*providerMeta).client
 	var machine *ovc.MachineInfo
 	var err error
 	if v, ok := d.GetOk("machine_id"); ok {
 		machine, err = client.Machines.Get(v.(int))
-		if err != nil {
-			return err
+	} else if v, ok := d.GetOk("reference_id"); ok {
+		machine, err = client.Machines.GetByReferenceID(v.(string))
+	} else if v, ok := d.GetOk("name"); ok {
+		cloudspaceID, ok := d.GetOk("cloudspace_id")
+		if !ok {
+			return fmt.Errorf("'cloudspace_id' is required to look up a machine by name")
 		}
+		machine, err = client.Machines.GetByName(v.(string), cloudspaceID.(int))
 	} else {
-		machine, err = client.Machines.GetByName(d.Get("name").(string), d.Get("cloudspace_id").(int))
-
-		if err != nil {
-			return err
-		}
+		return fmt.Errorf("Either 'machine_id', 'reference_id' or 'name' should be given to define machine datasource")
+	}
+	if err != nil {
+		return err
 	}
 	d.SetId(strconv.Itoa(machine.ID))
 	d.Set("status", machine.Status)
@@ -146,7 +203,7 @@ func dataSourceOvcMachineRead(d *schema.ResourceData, m interface{}) error {
 	d.Set("size_id", machine.SizeID)
 	d.Set("description", machine.Description)
 	d.Set("update_time", machine.UpdateTime)
-	d.Set("cloudspace_id", strconv.Itoa(machine.CloudspaceID))
+	d.Set("cloudspace_id", machine.CloudspaceID)
 	d.Set("machine_id", machine.ID)
 	d.Set("image_id", machine.ImageID)
 	d.Set("hostname", machine.Hostname)
@@ -154,6 +211,9 @@ func dataSourceOvcMachineRead(d *schema.ResourceData, m interface{}) error {
 	d.Set("os_image", machine.OsImage)
 	d.Set("storage", machine.Storage)
 	d.Set("locked", machine.Locked)
+	d.Set("vcpus", machine.Vcpus)
+	d.Set("memory", machine.Memory)
+	d.Set("disks", flattenDisks(machine))
 	interfaces := make([]map[string]interface{}, len(machine.Interfaces))
 	for i := range machine.Interfaces {
 		machineInterface := make(map[string]interface{})
@@ -175,6 +235,29 @@ func dataSourceOvcMachineRead(d *schema.ResourceData, m interface{}) error {
 		accounts[i] = account
 	}
 	d.Set("accounts", accounts)
-	return nil
+	acl := make([]map[string]interface{}, len(machine.ACL))
+	for i, ace := range machine.ACL {
+		acl[i] = map[string]interface{}{
+			"user_group_id":  ace.UserGroupID,
+			"right":          ace.Right,
+			"status":         ace.Status,
+			"type":           ace.Type,
+			"can_be_deleted": ace.CanBeDeleted,
+		}
+	}
+	d.Set("acl", acl)
 
+	// the reference ID is only part of the machine list
+	if _, ok := d.GetOk("reference_id"); !ok {
+		machines, err := client.Machines.List(machine.CloudspaceID)
+		if err != nil {
+			return err
+		}
+		for _, mc := range *machines {
+			if mc.ID == machine.ID {
+				d.Set("reference_id", mc.ReferenceID)
+			}
+		}
+	}
+	return nil
 }
@@ -1,21 +1,32 @@
 package ovc
 
 import (
+	"regexp"
 	"strconv"
 
 	"github.com/gig-tech/ovc-sdk-go/v3/ovc"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 )
 
 func dataSourceOvcMachines() *schema.Resource {
 	return &schema.Resource{
 		Read: dataSourceOvcMachinesRead,
 
-		Schema: map[string]*schema.Schema{
+		Schema: withFilters(map[string]*schema.Schema{
 			"cloudspace_id": {
 				Type:     schema.TypeString,
 				Required: true,
 			},
+			"status": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.ValidateRegexp,
+			},
 			"entities": {
 				Type:     schema.TypeList,
 				Computed: true,
@@ -53,15 +64,88 @@ func dataSourceOvcMachines() *schema.Resource {
 							Type:     schema.TypeFloat,
 							Computed: true,
 						},
+						"reference_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"vcpus": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"memory": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"storage": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"disk_ids": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeInt},
+						},
+						"nics": nicsSchema(),
 					},
 				},
 			},
+		}),
+	}
+}
+
+// nicsSchema describes the network interfaces of a machine
+func nicsSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"ip_address": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"mac_address": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"network_id": {
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+				"type": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"device_name": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"status": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+			},
 		},
 	}
 }
 
+func flattenMachineNics(nics []ovc.NIC) []map[string]interface{} {
+	result := make([]map[string]interface{}, len(nics))
+	for i, nic := range nics {
+		result[i] = map[string]interface{}{
+			"ip_address":  nic.IPAddress,
+			"mac_address": nic.MacAddress,
+			"network_id":  nic.NetworkID,
+			"type":        nic.Type,
+			"device_name": nic.DeviceName,
+			"status":      nic.Status,
+		}
+	}
+	return result
+}
+
 func dataSourceOvcMachinesRead(d *schema.ResourceData, m interface{}) error {
-	c := m.(*ovc.Client)
+	c := m.(*providerMeta).client
 	cloudspaceID := d.Get("cloudspace_id")
 	cid, err := strconv.Atoi(cloudspaceID.(string))
 	if err != nil {
@@ -71,21 +155,36 @@ func dataSourceOvcMachinesRead(d *schema.ResourceData, m interface{}) error {
 	if err != nil {
 		return err
 	}
-	entities := make([]map[string]interface{}, len(*machines))
-	for i, mc := range *machines {
+	re, err := regexp.Compile(d.Get("name_regex").(string))
+	if err != nil {
+		return err
+	}
+	status := d.Get("status").(string)
+
+	entities := make([]map[string]interface{}, 0, len(*machines))
+	for _, mc := range *machines {
+		if (status != "" && status != mc.Status) || !re.MatchString(mc.Name) {
+			continue
+		}
 		entity := make(map[string]interface{})
-		entity["machine_id"] = strconv.Itoa(mc.ID)
+		entity["machine_id"] = mc.ID
 		entity["name"] = mc.Name
 		entity["size_id"] = mc.SizeID
 		entity["image_id"] = mc.ImageID
 		entity["status"] = mc.Status
 		entity["update_time"] = mc.UpdateTime
 		entity["creationtime"] = mc.CreationTime
-		entities[i] = entity
+		entity["reference_id"] = mc.ReferenceID
+		entity["vcpus"] = mc.Vcpus
+		entity["memory"] = mc.Memory
+		entity["storage"] = mc.Storage
+		entity["disk_ids"] = mc.Disks
+		entity["nics"] = flattenMachineNics(mc.Nics)
+		entities = append(entities, entity)
 	}
-	if err := d.Set("entities", entities); err != nil {
+	if err := d.Set("entities", applyFilters(d, entities)); err != nil {
 		return err
 	}
-	d.SetId(cloudspaceID.(string))
+	d.SetId(queryID(d, "cloudspace_id", "status", "name_regex"))
 	return nil
 }
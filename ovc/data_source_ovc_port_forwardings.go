@@ -0,0 +1,114 @@
+package ovc
+
+import (
+	"github.com/gig-tech/ovc-sdk-go/v3/ovc"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+func dataSourceOvcPortForwardings() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceOvcPortForwardingsRead,
+
+		Schema: withFilters(map[string]*schema.Schema{
+			"cloudspace_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+			},
+			"machine_id": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"public_ip": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"protocol": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"tcp", "udp"}, false),
+			},
+			"entities": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"machine_id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"machine_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"protocol": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"public_ip": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"public_port": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"local_ip": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"local_port": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		}),
+	}
+}
+
+func dataSourceOvcPortForwardingsRead(d *schema.ResourceData, m interface{}) error {
+	client := m.(*providerMeta).client
+	list, err := client.Portforwards.List(&ovc.PortForwardingConfig{
+		CloudspaceID: d.Get("cloudspace_id").(int),
+		MachineID:    d.Get("machine_id").(int),
+	})
+	if err != nil {
+		return err
+	}
+
+	machineID := d.Get("machine_id").(int)
+	publicIP := d.Get("public_ip").(string)
+	protocol := d.Get("protocol").(string)
+
+	entities := make([]map[string]interface{}, 0, len(*list))
+	for _, port := range *list {
+		if (machineID != 0 && machineID != port.MachineID) ||
+			(publicIP != "" && publicIP != port.PublicIP) ||
+			(protocol != "" && protocol != port.Protocol) {
+			continue
+		}
+		entity := make(map[string]interface{})
+		entity["id"] = port.ID
+		entity["machine_id"] = port.MachineID
+		entity["machine_name"] = port.MachineName
+		entity["protocol"] = port.Protocol
+		entity["public_ip"] = port.PublicIP
+		entity["public_port"] = port.PublicPort
+		entity["local_ip"] = port.LocalIP
+		entity["local_port"] = port.LocalPort
+		entities = append(entities, entity)
+	}
+
+	if err = d.Set("entities", applyFilters(d, entities)); err != nil {
+		return err
+	}
+
+	d.SetId(queryID(d, "cloudspace_id", "machine_id", "public_ip", "protocol"))
+	return nil
+}
@@ -0,0 +1,118 @@
+package ovc
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/gig-tech/ovc-sdk-go/v3/ovc"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+func dataSourceOvcSize() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceOvcSizeRead,
+
+		Schema: map[string]*schema.Schema{
+			"cloudspace_id": {
+				Type:     schema.TypeInt,
+				Required: true,
+			},
+			"min_vcpus": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"min_memory": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.ValidateRegexp,
+			},
+			"size_id": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"vcpus": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"memory": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"disks": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeInt},
+			},
+		},
+	}
+}
+
+// bestFitSize returns the smallest size with at least minVcpus and minMemory whose name matches re.
+// Sizes are ordered by vcpus first and memory second.
+func bestFitSize(sizes []ovc.Size, minVcpus int, minMemory int, re *regexp.Regexp) (*ovc.Size, bool) {
+	candidates := make([]ovc.Size, 0, len(sizes))
+	for _, size := range sizes {
+		if size.Vcpus >= minVcpus && size.Memory >= minMemory && re.MatchString(size.Name) {
+			candidates = append(candidates, size)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, false
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Vcpus != candidates[j].Vcpus {
+			return candidates[i].Vcpus < candidates[j].Vcpus
+		}
+		if candidates[i].Memory != candidates[j].Memory {
+			return candidates[i].Memory < candidates[j].Memory
+		}
+		return candidates[i].ID < candidates[j].ID
+	})
+	return &candidates[0], true
+}
+
+func dataSourceOvcSizeRead(d *schema.ResourceData, m interface{}) error {
+	client := m.(*providerMeta).client
+	sizes, err := client.Sizes.List(d.Get("cloudspace_id").(int))
+	if err != nil {
+		return err
+	}
+
+	re, err := regexp.Compile(d.Get("name_regex").(string))
+	if err != nil {
+		return err
+	}
+	minVcpus := d.Get("min_vcpus").(int)
+	minMemory := d.Get("min_memory").(int)
+
+	size, ok := bestFitSize(*sizes, minVcpus, minMemory, re)
+	if !ok {
+		return fmt.Errorf("No size with at least %d vcpus and %d MB memory is allowed in cloudspace %d",
+			minVcpus, minMemory, d.Get("cloudspace_id").(int))
+	}
+
+	d.SetId(strconv.Itoa(size.ID))
+	d.Set("size_id", size.ID)
+	d.Set("name", size.Name)
+	d.Set("description", size.Description)
+	d.Set("vcpus", size.Vcpus)
+	d.Set("memory", size.Memory)
+	return d.Set("disks", size.Disks)
+}
@@ -0,0 +1,41 @@
+package ovc
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/gig-tech/ovc-sdk-go/v3/ovc"
+)
+
+func TestBestFitSize(t *testing.T) {
+	sizes := []ovc.Size{
+		{ID: 1, Name: "2vcpu-4gb", Vcpus: 2, Memory: 4096},
+		{ID: 2, Name: "4vcpu-16gb", Vcpus: 4, Memory: 16384},
+		{ID: 3, Name: "4vcpu-8gb", Vcpus: 4, Memory: 8192},
+		{ID: 4, Name: "8vcpu-8gb", Vcpus: 8, Memory: 8192},
+		{ID: 5, Name: "win-4vcpu-8gb", Vcpus: 4, Memory: 8192},
+	}
+	cases := []struct {
+		name      string
+		minVcpus  int
+		minMemory int
+		nameRegex string
+		want      int
+	}{
+		{"smallest", 0, 0, "", 1},
+		{"at least 4 vcpus and 6 GB", 4, 6144, "", 3},
+		{"memory bound", 0, 10000, "", 2},
+		{"name filter", 4, 0, "^win-", 5},
+		{"no match", 16, 0, "", 0},
+	}
+	for _, c := range cases {
+		size, ok := bestFitSize(sizes, c.minVcpus, c.minMemory, regexp.MustCompile(c.nameRegex))
+		got := 0
+		if ok {
+			got = size.ID
+		}
+		if got != c.want {
+			t.Errorf("%s: got size %d, want %d", c.name, got, c.want)
+		}
+	}
+}
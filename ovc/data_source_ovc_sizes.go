@@ -3,7 +3,6 @@ package ovc
 import (
 	"strconv"
 
-	"github.com/gig-tech/ovc-sdk-go/v3/ovc"
 	"github.com/hashicorp/terraform/helper/schema"
 )
 
@@ -11,7 +10,7 @@ func dataSourceOvcSizes() *schema.Resource {
 	return &schema.Resource{
 		Read: dataSourceOvcSizesRead,
 
-		Schema: map[string]*schema.Schema{
+		Schema: withFilters(map[string]*schema.Schema{
 			"sizes_id": {
 				Type:     schema.TypeInt,
 				Optional: true,
@@ -37,13 +36,72 @@ func dataSourceOvcSizes() *schema.Resource {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
-		},
+			"entities": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"description": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"vcpus": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"memory": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"disks": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeInt},
+						},
+					},
+				},
+			},
+		}),
 	}
 }
 
 func dataSourceOvcSizesRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(*ovc.Client)
-	sid, err := client.Sizes.GetByVcpusAndMemory(d.Get("vcpus").(int), d.Get("memory").(int), d.Get("cloudspace_id").(int))
+	client := m.(*providerMeta).client
+	cloudspaceID := d.Get("cloudspace_id").(int)
+	sizes, err := client.Sizes.List(cloudspaceID)
+	if err != nil {
+		return err
+	}
+	entities := make([]map[string]interface{}, len(*sizes))
+	for i, size := range *sizes {
+		entity := make(map[string]interface{})
+		entity["id"] = size.ID
+		entity["name"] = size.Name
+		entity["description"] = size.Description
+		entity["vcpus"] = size.Vcpus
+		entity["memory"] = size.Memory
+		entity["disks"] = size.Disks
+		entities[i] = entity
+	}
+	if err := d.Set("entities", applyFilters(d, entities)); err != nil {
+		return err
+	}
+
+	// without vcpus and memory only the list of sizes is returned
+	vcpus, memory := d.Get("vcpus").(int), d.Get("memory").(int)
+	if vcpus == 0 && memory == 0 {
+		d.SetId(queryID(d, "cloudspace_id"))
+		return nil
+	}
+	sid, err := client.Sizes.GetByVcpusAndMemory(vcpus, memory, cloudspaceID)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,124 @@
+package ovc
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+func dataSourceOvcTemplates() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceOvcTemplatesRead,
+
+		Schema: withFilters(map[string]*schema.Schema{
+			"account": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"status": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"type": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.ValidateRegexp,
+			},
+			"entities": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"account_id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"description": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"size": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"username": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		}),
+	}
+}
+
+func dataSourceOvcTemplatesRead(d *schema.ResourceData, m interface{}) error {
+	client := m.(*providerMeta).client
+	accountID, err := client.Accounts.GetIDByName(d.Get("account").(string))
+	if err != nil {
+		return err
+	}
+
+	templates, err := client.Templates.List(accountID)
+	if err != nil {
+		return err
+	}
+
+	re, err := regexp.Compile(d.Get("name_regex").(string))
+	if err != nil {
+		return err
+	}
+	status := d.Get("status").(string)
+	templateType := d.Get("type").(string)
+
+	entities := make([]map[string]interface{}, 0, len(*templates))
+	for _, template := range *templates {
+		if (status != "" && status != template.Status) ||
+			(templateType != "" && templateType != template.Type) ||
+			!re.MatchString(template.Name) {
+			continue
+		}
+		entity := make(map[string]interface{})
+		entity["id"] = template.ID
+		entity["account_id"] = template.AccountID
+		entity["name"] = template.Name
+		entity["description"] = template.Description
+		entity["status"] = template.Status
+		entity["type"] = template.Type
+		entity["size"] = template.Size
+		entity["username"] = ""
+		if template.Username != nil {
+			entity["username"] = fmt.Sprint(template.Username)
+		}
+		entities = append(entities, entity)
+	}
+
+	if err = d.Set("entities", applyFilters(d, entities)); err != nil {
+		return err
+	}
+
+	d.SetId(queryID(d, "account", "status", "type", "name_regex"))
+	return nil
+}
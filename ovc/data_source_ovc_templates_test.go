@@ -0,0 +1,37 @@
+package ovc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTemplatesAreListedForTheAccount(t *testing.T) {
+	accountID := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == taskEndpoint {
+			fmt.Fprint(w, `[true, []]`)
+			return
+		}
+		var in struct {
+			AccountID int `json:"accountId"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			t.Error(err)
+		}
+		accountID = in.AccountID
+		fmt.Fprint(w, `"guid-1"`)
+	}))
+	defer server.Close()
+
+	api := newTestAPIClient(t, context.Background(), server, retryPolicy{})
+	if _, err := (&templateService{api: api}).List(7); err != nil {
+		t.Fatal(err)
+	}
+	if accountID != 7 {
+		t.Errorf("templates listed for account %d, want 7", accountID)
+	}
+}
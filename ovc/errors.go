@@ -0,0 +1,286 @@
+package ovc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/gig-tech/ovc-sdk-go/v3/ovc"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// apiError is returned when the G8 API responds with an error status or a task fails
+type apiError struct {
+	StatusCode int
+	Endpoint   string
+	TaskID     string
+	// Message is the error message of the G8, without the traceback of the API server
+	Message string
+}
+
+func newAPIError(endpoint string, statusCode int, body []byte) *apiError {
+	status, message := parseErrorMessage(body)
+	if statusCode == 0 {
+		statusCode = status
+	}
+	return &apiError{
+		StatusCode: statusCode,
+		Endpoint:   endpoint,
+		Message:    message,
+	}
+}
+
+// newTaskError returns the error of a task that finished unsuccessfully with result
+func newTaskError(endpoint string, taskID string, result []interface{}) *apiError {
+	var body []byte
+	if len(result) > 1 {
+		body, _ = json.Marshal(result[1])
+	}
+	err := newAPIError(endpoint, 0, body)
+	err.TaskID = taskID
+	if err.Message == "" {
+		err.Message = "task was not successful"
+	}
+	return err
+}
+
+func (e *apiError) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Endpoint)
+	if e.StatusCode != 0 {
+		fmt.Fprintf(&b, " returned %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	if e.TaskID != "" {
+		fmt.Fprintf(&b, " (task %s)", e.TaskID)
+	}
+	fmt.Fprintf(&b, ": %s", e.Message)
+	if hint := e.hint(); hint != "" {
+		fmt.Fprintf(&b, "\n\n%s", hint)
+	}
+	return b.String()
+}
+
+// hint suggests how to resolve the error for conditions users can act on
+func (e *apiError) hint() string {
+	switch {
+	case e.StatusCode == http.StatusLocked || lockedPattern.MatchString(e.Message):
+		return "The machine is locked by another action on the G8, like a snapshot, migration or backup. " +
+			"Run the apply again once that action finished."
+	case quotaPattern.MatchString(e.Message):
+		return "The resource limits of the cloudspace or account do not allow this. " +
+			"Raise the resource_limits of the cloudspace, ask the account owner to raise those of the account, or free up resources."
+	case e.StatusCode == http.StatusConflict || conflictPattern.MatchString(e.Message):
+		return "An object with the same name or port already exists on the G8. " +
+			"Pick another name or port, or bring the existing object under Terraform with terraform import."
+	}
+	return ""
+}
+
+// Fragments of the G8 error messages the hints are given for
+var (
+	lockedPattern   = regexp.MustCompile(`(?i)\b(is|are|was) (currently )?locked\b`)
+	quotaPattern    = regexp.MustCompile(`(?i)\bquota\b|\bresource limits?\b|\bexceeds? the (maximum|max|available|allowed|reserved)\b`)
+	conflictPattern = regexp.MustCompile(`(?i)\balready (exists|in use)\b`)
+)
+
+// Is makes the api error match the sentinel errors of the SDK
+func (e *apiError) Is(target error) bool {
+	switch target {
+	case ovc.ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ovc.ErrAuthentication:
+		return e.StatusCode == http.StatusUnauthorized
+	}
+	return false
+}
+
+// parseErrorMessage extracts the status and message from the error body of a call or the result of a failed task.
+// The G8 returns them as JSON strings, objects or [status, message] pairs, often with a python traceback.
+func parseErrorMessage(body []byte) (int, string) {
+	text := strings.TrimSpace(string(body))
+	if strings.HasPrefix(text, "<") {
+		// an HTML page of the web server in front of the G8
+		if match := htmlTitlePattern.FindStringSubmatch(text); match != nil {
+			return 0, match[1]
+		}
+		return 0, ""
+	}
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return 0, lastTracebackLine(text)
+	}
+	return errorMessageOf(value)
+}
+
+var htmlTitlePattern = regexp.MustCompile(`(?is)<title>\s*(.*?)\s*</title>`)
+
+func errorMessageOf(value interface{}) (int, string) {
+	switch v := value.(type) {
+	case nil:
+		return 0, ""
+	case string:
+		if strings.HasPrefix(strings.TrimSpace(v), "{") || strings.HasPrefix(strings.TrimSpace(v), "[") {
+			return parseErrorMessage([]byte(v))
+		}
+		return 0, lastTracebackLine(strings.TrimSpace(v))
+	case map[string]interface{}:
+		status := 0
+		for _, key := range []string{"status", "status_code", "code"} {
+			if code, ok := v[key].(float64); ok {
+				status = int(code)
+				break
+			}
+		}
+		for _, key := range []string{"message", "error", "msg", "description"} {
+			if message, ok := v[key]; ok {
+				messageStatus, text := errorMessageOf(message)
+				if status == 0 {
+					status = messageStatus
+				}
+				return status, text
+			}
+		}
+		raw, _ := json.Marshal(v)
+		return status, string(raw)
+	case []interface{}:
+		if len(v) == 2 {
+			if code, ok := v[0].(float64); ok {
+				_, message := errorMessageOf(v[1])
+				return int(code), message
+			}
+		}
+	}
+	return 0, fmt.Sprint(value)
+}
+
+// lastTracebackLine returns the exception line of a python traceback, or the text when it is not a traceback
+func lastTracebackLine(text string) string {
+	if !strings.Contains(text, "Traceback (most recent call last)") {
+		return text
+	}
+	lines := strings.Split(text, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		if line := strings.TrimSpace(lines[i]); line != "" {
+			return line
+		}
+	}
+	return text
+}
+
+// taskInterruptedError is returned when waiting for a G8 task stopped before the task finished,
+// because the operation timed out or Terraform was interrupted. The task keeps running on the G8.
+type taskInterruptedError struct {
+	Endpoint string
+	TaskID   string
+	Err      error
+}
+
+func (e *taskInterruptedError) Error() string {
+	return fmt.Sprintf("%s: stopped waiting for task %s, it may still complete on the G8: %v", e.Endpoint, e.TaskID, e.Err)
+}
+
+func (e *taskInterruptedError) Unwrap() error {
+	return e.Err
+}
+
+// interruptedTask returns the GUID of the task err stopped waiting for
+func interruptedTask(err error) (string, bool) {
+	var interrupted *taskInterruptedError
+	if errors.As(err, &interrupted) {
+		return interrupted.TaskID, true
+	}
+	return "", false
+}
+
+// taskLostError is returned when the G8 keeps reporting a started task as not found.
+// It is not a not found error: the object the task was started for may well exist.
+type taskLostError struct {
+	Endpoint string
+	TaskID   string
+	Message  string
+}
+
+func (e *taskLostError) Error() string {
+	return fmt.Sprintf("%s: the G8 no longer knows task %s, its outcome is unknown: %s", e.Endpoint, e.TaskID, e.Message)
+}
+
+// isTaskLost reports whether err means the G8 does not know the task that was waited for
+func isTaskLost(err error) bool {
+	var lost *taskLostError
+	return errors.As(err, &lost)
+}
+
+// isNotFound reports whether err means the requested object does not exist on the G8.
+// Only these errors allow a resource to be removed from the state.
+func isNotFound(err error) bool {
+	return errors.Is(err, ovc.ErrNotFound)
+}
+
+// withErrorContext makes the errors of the functions of a resource or data source
+// name the operation and the object they failed for
+func withErrorContext(name string, r *schema.Resource, dataSource bool) {
+	kind := name
+	if dataSource {
+		kind = "data source " + name
+	}
+	key := identifyingKey(r)
+	nameOf := func(get func(string) interface{}) string {
+		if key == "" {
+			return ""
+		}
+		name, _ := get(key).(string)
+		return name
+	}
+	wrap := func(operation string, f func(*schema.ResourceData, interface{}) error) func(*schema.ResourceData, interface{}) error {
+		if f == nil {
+			return nil
+		}
+		return func(d *schema.ResourceData, m interface{}) error {
+			if err := f(d, m); err != nil {
+				return fmt.Errorf("error %s %s%s: %w", operation, kind, objectAddress(d.Id(), nameOf(d.Get)), err)
+			}
+			return nil
+		}
+	}
+	r.Create = wrap("creating", r.Create)
+	r.Read = wrap("reading", r.Read)
+	r.Update = wrap("updating", r.Update)
+	r.Delete = wrap("deleting", r.Delete)
+	if customizeDiff := r.CustomizeDiff; customizeDiff != nil {
+		r.CustomizeDiff = func(diff *schema.ResourceDiff, m interface{}) error {
+			if err := customizeDiff(diff, m); err != nil {
+				return fmt.Errorf("error planning %s%s: %w", kind, objectAddress(diff.Id(), nameOf(diff.Get)), err)
+			}
+			return nil
+		}
+	}
+}
+
+// identifyingKeys are the arguments that name an object in errors, in order of preference
+var identifyingKeys = []string{"name", "disk_name", "code", "remote_public_ip", "public_ip"}
+
+// identifyingKey returns the first of identifyingKeys in the schema of r, or "" when it has none
+func identifyingKey(r *schema.Resource) string {
+	for _, key := range identifyingKeys {
+		if s, ok := r.Schema[key]; ok && s.Type == schema.TypeString {
+			return key
+		}
+	}
+	return ""
+}
+
+// objectAddress describes an object by its name and ID, as far as they are known
+func objectAddress(id string, name string) string {
+	switch {
+	case name != "" && id != "":
+		return fmt.Sprintf(" %q (%s)", name, id)
+	case name != "":
+		return fmt.Sprintf(" %q", name)
+	case id != "":
+		return fmt.Sprintf(" %s", id)
+	}
+	return ""
+}
@@ -0,0 +1,140 @@
+package ovc
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gig-tech/ovc-sdk-go/v3/ovc"
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestIsNotFound(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"sdk not found", ovc.ErrNotFound, true},
+		{"api 404", newAPIError("/cloudapi/machines/get", http.StatusNotFound, []byte("Machine not found")), true},
+		{"wrapped 404", fmt.Errorf("lookup: %w", newAPIError("/cloudapi/disks/get", http.StatusNotFound, nil)), true},
+		{"api 500", newAPIError("/cloudapi/machines/get", http.StatusInternalServerError, []byte("boom")), false},
+		{"api 401", newAPIError("/cloudapi/machines/get", http.StatusUnauthorized, nil), false},
+		{"sdk auth", ovc.ErrAuthentication, false},
+		{"task failure", &apiError{Endpoint: "/cloudapi/machines/get", Message: "Task was not successful"}, false},
+		{"plain error", errors.New("connection reset by peer"), false},
+	}
+	for _, c := range cases {
+		if got := isNotFound(c.err); got != c.want {
+			t.Errorf("%s: isNotFound(%v) = %v, want %v", c.name, c.err, got, c.want)
+		}
+	}
+}
+
+func TestAPIErrorMatchesAuthentication(t *testing.T) {
+	err := newAPIError("/cloudapi/accounts/list", http.StatusUnauthorized, []byte("expired"))
+	if !errors.Is(err, ovc.ErrAuthentication) {
+		t.Errorf("expected %v to match ovc.ErrAuthentication", err)
+	}
+}
+
+func TestParseErrorMessage(t *testing.T) {
+	cases := []struct {
+		body    string
+		status  int
+		message string
+	}{
+		{`"Machine not found"`, 0, "Machine not found"},
+		{`{"status": 409, "message": "Machine with name web already exists"}`, 409, "Machine with name web already exists"},
+		{`[423, "Machine is locked"]`, 423, "Machine is locked"},
+		{"\"Traceback (most recent call last):\\n  File \\\"x.py\\\", line 1\\nexceptions.Conflict: Not enough memory quota\\n\"", 0, "exceptions.Conflict: Not enough memory quota"},
+		{nginxBadRequestBody, 0, "400 Bad Request"},
+		{"boom", 0, "boom"},
+	}
+	for _, c := range cases {
+		status, message := parseErrorMessage([]byte(c.body))
+		if status != c.status || message != c.message {
+			t.Errorf("parseErrorMessage(%q) = %d, %q, want %d, %q", c.body, status, message, c.status, c.message)
+		}
+	}
+}
+
+func TestTaskErrorHint(t *testing.T) {
+	err := newTaskError("/cloudapi/machines/create", "guid", []interface{}{false, map[string]interface{}{"status": 409.0, "message": "Machine with name web already exists"}})
+	if err.StatusCode != http.StatusConflict || err.TaskID != "guid" {
+		t.Errorf("unexpected error %+v", err)
+	}
+	if !strings.Contains(err.Error(), "terraform import") {
+		t.Errorf("expected a hint for the name conflict in %q", err)
+	}
+}
+
+func TestWithErrorContext(t *testing.T) {
+	r := resourceOvcDisk()
+	r.Create = func(d *schema.ResourceData, m interface{}) error {
+		return newAPIError("/cloudapi/machines/addDisk", http.StatusInternalServerError, []byte("boom"))
+	}
+	withErrorContext("ovc_disk", r, false)
+	d := r.TestResourceData()
+	err := r.Create(d, nil)
+	want := "error creating ovc_disk: /cloudapi/machines/addDisk returned 500 Internal Server Error: boom"
+	if err == nil || err.Error() != want {
+		t.Errorf("error = %v, want %s", err, want)
+	}
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) {
+		t.Errorf("the api error is not wrapped")
+	}
+}
+
+func TestErrorHints(t *testing.T) {
+	tests := []struct {
+		status  int
+		message string
+		hint    string
+	}{
+		{http.StatusLocked, "Machine is busy", "locked"},
+		{http.StatusBadRequest, "Machine is locked by a snapshot", "locked"},
+		{http.StatusBadRequest, "Port 22 is blocked by the firewall", ""},
+		{http.StatusBadRequest, "Machine was unlocked", ""},
+		{http.StatusBadRequest, "Required memory exceeds the available memory of the cloudspace", "resource limits"},
+		{http.StatusBadRequest, "Not enough memory quota", "resource limits"},
+		{http.StatusBadRequest, "Request exceeded the retry count", ""},
+		{http.StatusBadRequest, "Machine with name web already exists", "terraform import"},
+		{http.StatusConflict, "Conflict", "terraform import"},
+	}
+	for _, test := range tests {
+		hint := (&apiError{StatusCode: test.status, Message: test.message}).hint()
+		if test.hint == "" && hint != "" || !strings.Contains(hint, test.hint) {
+			t.Errorf("%d %s: hint %q, want one about %q", test.status, test.message, hint, test.hint)
+		}
+	}
+}
+
+func TestWithErrorContextNamesTheObject(t *testing.T) {
+	r := resourceOvcDisk()
+	r.Create = func(d *schema.ResourceData, m interface{}) error {
+		return errors.New("boom")
+	}
+	withErrorContext("ovc_disk", r, false)
+	d := r.TestResourceData()
+	d.Set("disk_name", "data")
+	if err := r.Create(d, nil); err == nil || err.Error() != `error creating ovc_disk "data": boom` {
+		t.Errorf("error = %v", err)
+	}
+
+	// port forwards have no name argument
+	r = resourcePortForwarding()
+	r.Read = func(d *schema.ResourceData, m interface{}) error {
+		return errors.New("boom")
+	}
+	withErrorContext("ovc_port_forwarding", r, false)
+	d = r.TestResourceData()
+	d.SetId("12")
+	d.Set("public_ip", "192.0.2.1")
+	if err := r.Read(d, nil); err == nil || err.Error() != `error reading ovc_port_forwarding "192.0.2.1" (12): boom` {
+		t.Errorf("error = %v", err)
+	}
+}
@@ -0,0 +1,91 @@
+package ovc
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+)
+
+// withFilters adds the filter block to the schema of a plural data source.
+// Filters can select on every primitive attribute of the entities.
+func withFilters(s map[string]*schema.Schema) map[string]*schema.Schema {
+	var attributes []string
+	for name, attr := range s["entities"].Elem.(*schema.Resource).Schema {
+		switch attr.Type {
+		case schema.TypeString, schema.TypeInt, schema.TypeBool, schema.TypeFloat:
+			attributes = append(attributes, name)
+		}
+	}
+	sort.Strings(attributes)
+
+	s["filter"] = &schema.Schema{
+		Type:     schema.TypeSet,
+		Optional: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:         schema.TypeString,
+					Required:     true,
+					ValidateFunc: validation.StringInSlice(attributes, false),
+				},
+				"values": {
+					Type:     schema.TypeList,
+					Required: true,
+					MinItems: 1,
+					Elem:     &schema.Schema{Type: schema.TypeString},
+				},
+			},
+		},
+	}
+	return s
+}
+
+// applyFilters returns the entities matching all filter blocks of d.
+// An entity matches a filter when its attribute equals one of the values.
+func applyFilters(d *schema.ResourceData, entities []map[string]interface{}) []map[string]interface{} {
+	filters := d.Get("filter").(*schema.Set).List()
+	if len(filters) == 0 {
+		return entities
+	}
+	result := make([]map[string]interface{}, 0, len(entities))
+	for _, entity := range entities {
+		if matchesFilters(entity, filters) {
+			result = append(result, entity)
+		}
+	}
+	return result
+}
+
+func matchesFilters(entity map[string]interface{}, filters []interface{}) bool {
+	for _, f := range filters {
+		filter := f.(map[string]interface{})
+		value := fmt.Sprint(entity[filter["name"].(string)])
+		matched := false
+		for _, v := range filter["values"].([]interface{}) {
+			if v.(string) == value {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// queryID returns the ID of a plural data source as a hash of its query arguments
+func queryID(d *schema.ResourceData, arguments ...string) string {
+	query := make([]string, 0, len(arguments)+1)
+	for _, argument := range append(arguments, "filter") {
+		value := d.Get(argument)
+		if set, ok := value.(*schema.Set); ok {
+			value = set.List()
+		}
+		query = append(query, fmt.Sprintf("%s=%v", argument, value))
+	}
+	return hashcode.Strings(query)
+}
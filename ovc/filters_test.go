@@ -0,0 +1,37 @@
+package ovc
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+func TestApplyFilters(t *testing.T) {
+	d := dataSourceOvcLocations().TestResourceData()
+	if err := d.Set("filter", []interface{}{
+		map[string]interface{}{"name": "grid_id", "values": []interface{}{"1", "3"}},
+		map[string]interface{}{"name": "flag", "values": []interface{}{"ch"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	entities := []map[string]interface{}{
+		{"id": 1, "code": "ch-lug-dc01-001", "grid_id": 1, "flag": "ch"},
+		{"id": 2, "code": "be-g8-3", "grid_id": 2, "flag": "be"},
+		{"id": 3, "code": "ch-gen-1", "grid_id": 3, "flag": "ch"},
+		{"id": 4, "code": "be-g8-4", "grid_id": 3, "flag": "be"},
+	}
+	got := applyFilters(d, entities)
+	if len(got) != 2 || got[0]["id"] != 1 || got[1]["id"] != 3 {
+		t.Errorf("unexpected entities %v", got)
+	}
+}
+
+func TestFilterNameIsValidated(t *testing.T) {
+	filter := dataSourceOvcLocations().Schema["filter"].Elem.(*schema.Resource).Schema["name"]
+	if _, errs := filter.ValidateFunc("grid_id", "name"); len(errs) != 0 {
+		t.Errorf("grid_id should be a valid filter: %v", errs)
+	}
+	if _, errs := filter.ValidateFunc("gid", "name"); len(errs) == 0 {
+		t.Error("gid should not be a valid filter")
+	}
+}
@@ -0,0 +1,65 @@
+package ovc
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/gig-tech/ovc-sdk-go/v3/ovc"
+)
+
+// locationResolver looks up G8 locations by their code, the list of
+// locations is fetched once per provider
+type locationResolver struct {
+	// location of the provider, used when a resource does not set one
+	defaultLocation string
+
+	mu        sync.Mutex
+	locations ovc.LocationList
+}
+
+// resolve returns the location with the given code or name. An empty name resolves to the
+// location of the provider, or to the only location of the G8 when the provider has none.
+func (r *locationResolver) resolve(service ovc.LocationService, name string) (*ovc.LocationInfo, error) {
+	locations, err := r.list(service)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		name = r.defaultLocation
+	}
+	if name == "" {
+		if len(locations) == 1 {
+			return &locations[0], nil
+		}
+		return nil, fmt.Errorf("the G8 has %d locations (%s), set the location argument of the provider or resource",
+			len(locations), locationCodes(locations))
+	}
+	for i := range locations {
+		if locations[i].Code == name || locations[i].Name == name {
+			return &locations[i], nil
+		}
+	}
+	return nil, fmt.Errorf("location %q does not exist, available locations are: %s", name, locationCodes(locations))
+}
+
+func (r *locationResolver) list(service ovc.LocationService) (ovc.LocationList, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.locations == nil {
+		locations, err := service.List()
+		if err != nil {
+			return nil, fmt.Errorf("error listing locations: %w", err)
+		}
+		r.locations = *locations
+	}
+	return r.locations, nil
+}
+
+func locationCodes(locations ovc.LocationList) string {
+	codes := make([]string, 0, len(locations))
+	for _, location := range locations {
+		codes = append(codes, location.Code)
+	}
+	return strings.Join(codes, ", ")
+}
@@ -0,0 +1,108 @@
+package ovc
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+)
+
+// lockKind separates the ID spaces of the objects that can be locked
+type lockKind int
+
+const (
+	cloudspaceLock lockKind = iota
+	machineLock
+)
+
+// lockKey identifies a machine or cloudspace that operations must not change concurrently
+type lockKey struct {
+	kind lockKind
+	id   int
+}
+
+func machineKey(id int) lockKey {
+	return lockKey{kind: machineLock, id: id}
+}
+
+func cloudspaceKey(id int) lockKey {
+	return lockKey{kind: cloudspaceLock, id: id}
+}
+
+func (k lockKey) String() string {
+	if k.kind == cloudspaceLock {
+		return fmt.Sprintf("cloudspace %d", k.id)
+	}
+	return fmt.Sprintf("machine %d", k.id)
+}
+
+// lockManager serializes the mutating operations of the provider on the same machine or cloudspace.
+// The G8 rejects concurrent actions on a machine as locked, and port forwards of a cloudspace
+// pick their public port from the ports that are not in use yet.
+type lockManager struct {
+	mu    sync.Mutex
+	locks map[lockKey]chan struct{}
+}
+
+func newLockManager() *lockManager {
+	return &lockManager{locks: make(map[lockKey]chan struct{})}
+}
+
+// lock acquires the locks of all keys, in a fixed order so operations locking several objects can not deadlock.
+// Keys with ID 0 are skipped. It fails when ctx is done before all locks are acquired.
+func (l *lockManager) lock(ctx context.Context, keys ...lockKey) (func(), error) {
+	keys = sortedKeys(keys)
+	acquired := make([]chan struct{}, 0, len(keys))
+	unlock := func() {
+		for i := len(acquired) - 1; i >= 0; i-- {
+			<-acquired[i]
+		}
+	}
+	for _, key := range keys {
+		ch := l.get(key)
+		select {
+		case ch <- struct{}{}:
+		default:
+			log.Printf("[DEBUG] Waiting for the lock of %s", key)
+			select {
+			case ch <- struct{}{}:
+			case <-ctx.Done():
+				unlock()
+				return nil, fmt.Errorf("waiting for the lock of %s: %w", key, ctx.Err())
+			}
+		}
+		acquired = append(acquired, ch)
+	}
+	return unlock, nil
+}
+
+func (l *lockManager) get(key lockKey) chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	ch, ok := l.locks[key]
+	if !ok {
+		ch = make(chan struct{}, 1)
+		l.locks[key] = ch
+	}
+	return ch
+}
+
+// sortedKeys returns the distinct non-zero keys, cloudspaces before machines
+func sortedKeys(keys []lockKey) []lockKey {
+	seen := make(map[lockKey]bool, len(keys))
+	result := make([]lockKey, 0, len(keys))
+	for _, key := range keys {
+		if key.id != 0 && !seen[key] {
+			seen[key] = true
+			result = append(result, key)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].kind != result[j].kind {
+			return result[i].kind < result[j].kind
+		}
+		return result[i].id < result[j].id
+	})
+	return result
+}
@@ -0,0 +1,80 @@
+package ovc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLockManager(t *testing.T) {
+	locks := newLockManager()
+	unlock, err := locks.lock(context.Background(), machineKey(1), cloudspaceKey(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the cloudspace with the same ID as the machine is another lock
+	unlockCloudspace2, err := locks.lock(context.Background(), cloudspaceKey(2), machineKey(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	unlockCloudspace2()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := locks.lock(ctx, machineKey(2), machineKey(1)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the lock of machine 1 to time out, got %v", err)
+	}
+	// machine 2 was released when waiting for machine 1 failed
+	unlockMachine2, err := locks.lock(context.Background(), machineKey(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	unlockMachine2()
+
+	acquired := make(chan struct{})
+	go func() {
+		unlock, err := locks.lock(context.Background(), machineKey(1))
+		if err == nil {
+			unlock()
+		}
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("machine 1 was locked twice")
+	case <-time.After(20 * time.Millisecond):
+	}
+	unlock()
+	<-acquired
+}
+
+func TestLockWaitEndsWithTheTimeout(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	meta := newProviderMeta(newTestAPIClient(t, context.Background(), server, retryPolicy{}), "ci")
+	unlock, err := meta.lock(context.Background(), machineKey(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unlock()
+
+	_, ctx, cancel := meta.withTimeout(50 * time.Millisecond)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		_, err := meta.lock(ctx, machineKey(1))
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected the lock wait to time out, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("waiting for the lock did not end with the timeout")
+	}
+}
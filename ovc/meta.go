@@ -0,0 +1,49 @@
+package ovc
+
+import (
+	"context"
+	"time"
+
+	"github.com/gig-tech/ovc-sdk-go/v3/ovc"
+)
+
+// providerMeta is handed to all resources and data sources by providerConfigure
+type providerMeta struct {
+	client *ovc.Client
+	api    *apiClient
+	locks  *lockManager
+}
+
+func newProviderMeta(api *apiClient, access string) *providerMeta {
+	client := &ovc.Client{
+		ServerURL: api.serverURL,
+		Access:    access,
+	}
+	api.useServices(client)
+	return &providerMeta{
+		client: client,
+		api:    api,
+		locks:  newLockManager(),
+	}
+}
+
+// location returns the G8 location with the given code, or the location of the provider when name is empty
+func (p *providerMeta) location(name string) (*ovc.LocationInfo, error) {
+	return p.api.location(name)
+}
+
+// withTimeout returns a client whose API calls, including waiting for their
+// tasks on the G8, fail once timeout has passed or Terraform is interrupted,
+// and the context that ends then
+func (p *providerMeta) withTimeout(timeout time.Duration) (*ovc.Client, context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(p.api.ctx, timeout)
+	client := *p.client
+	p.api.withContext(ctx).useServices(&client)
+	return &client, ctx, cancel
+}
+
+// lock serializes mutating operations on the given machines and cloudspaces, the returned function releases the locks.
+// Waiting for a lock ends with ctx, pass the context of withTimeout so the wait counts towards the timeout of the operation.
+func (p *providerMeta) lock(ctx context.Context, keys ...lockKey) (func(), error) {
+	return p.locks.lock(ctx, keys...)
+}
@@ -1,17 +1,22 @@
 package ovc
 
 import (
+	"context"
+	"fmt"
+	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/gig-tech/ovc-sdk-go/v3/ovc"
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	"github.com/sirupsen/logrus"
 )
 
 // Provider method to define all user inputs
 func Provider() *schema.Provider {
-	return &schema.Provider{
+	p := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"server_url": {
 				Type:        schema.TypeString,
@@ -22,38 +27,166 @@ func Provider() *schema.Provider {
 			"client_id": {
 				Type:          schema.TypeString,
 				Optional:      true,
-				ConflictsWith: []string{"client_jwt"},
+				ConflictsWith: []string{"client_jwt", "client_jwt_file", "credential_process"},
 				DefaultFunc:   schema.EnvDefaultFunc("ITSYOU_ONLINE_CLIENT_ID", nil),
 				Description:   "Client Id",
 			},
 			"client_secret": {
 				Type:          schema.TypeString,
 				Optional:      true,
-				ConflictsWith: []string{"client_jwt"},
+				ConflictsWith: []string{"client_jwt", "client_jwt_file", "credential_process"},
 				DefaultFunc:   schema.EnvDefaultFunc("ITSYOU_ONLINE_CLIENT_SECRET", nil),
 				Description:   "Client Secret",
 			},
 			"client_jwt": {
 				Type:          schema.TypeString,
 				Optional:      true,
-				ConflictsWith: []string{"client_id", "client_secret"},
+				ConflictsWith: []string{"client_id", "client_secret", "client_jwt_file", "credential_process"},
 				DefaultFunc:   schema.EnvDefaultFunc("ITSYOU_ONLINE_CLIENT_JWT", nil),
 				Description:   "Client JWT",
 			},
+			"client_jwt_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"client_id", "client_secret", "client_jwt", "credential_process"},
+				DefaultFunc:   schema.EnvDefaultFunc("OVC_CLIENT_JWT_FILE", nil),
+				Description:   "File holding the client JWT, it is read again when the JWT expires",
+			},
+			"credential_process": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"client_id", "client_secret", "client_jwt", "client_jwt_file"},
+				DefaultFunc:   schema.EnvDefaultFunc("OVC_CREDENTIAL_PROCESS", nil),
+				Description:   "Command printing a client JWT or client credentials, it is run again when the JWT expires",
+			},
+			"identity_provider": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("OVC_IDENTITY_PROVIDER", identityProviderIYO),
+				ValidateFunc: validation.StringInSlice([]string{identityProviderIYO, identityProviderOIDC}, false),
+				Description:  "Identity provider issuing the JWT, either itsyouonline or oidc",
+			},
+			"token_url": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("OVC_TOKEN_URL", ""),
+				Description: "Token endpoint of the identity provider, defaults to itsyou.online",
+			},
+			"scopes": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Scopes to request the JWT with",
+			},
+			"username_claim": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Claim of the JWT holding the G8 username, defaults to username for itsyouonline and preferred_username for oidc",
+			},
+			"location": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("OVC_LOCATION", ""),
+				Description: "Code of the G8 location, e.g. ch-lug-dc01-001",
+			},
+			"max_concurrent_requests": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("G8_API_CONCURRENT_REQUESTS", 5),
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Maximum number of concurrent model requests to the G8 API, the default of the other limits",
+			},
+			"max_concurrent_operational_requests": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("G8_API_CONCURRENT_OPERATIONAL_REQUESTS", nil),
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Maximum number of concurrent operational requests to the G8 API, like creating machines",
+			},
+			"max_concurrent_data_requests": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("G8_API_CONCURRENT_DATA_REQUESTS", nil),
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Maximum number of concurrent data requests to the G8 API, like uploading images",
+			},
+			"max_concurrent_task_polls": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("G8_API_CONCURRENT_TASK_POLLS", nil),
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Maximum number of concurrent requests polling the G8 API for the result of a task",
+			},
+			"max_retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      20,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Maximum number of retries of a throttled or failed G8 API request",
+			},
+			"retry_min_backoff": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "1s",
+				ValidateFunc: validateDuration,
+				Description:  "Minimum time to wait before retrying a G8 API request",
+			},
+			"retry_max_backoff": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "30s",
+				ValidateFunc: validateDuration,
+				Description:  "Maximum time to wait before retrying a G8 API request",
+			},
+			"task_poll_max_interval": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "2s",
+				ValidateFunc: validateDuration,
+				Description:  "Maximum time between polls for the result of a G8 task, the first polls are quicker",
+			},
+			"api_log_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("G8_API_ACCESS_LOG_FILE", ""),
+				Description: "File to write the G8 API access log to",
+			},
+			"api_log_level": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("G8_API_ACCESS_LOG_LEVEL", "info"),
+				ValidateFunc: validateLogLevel,
+				Description:  "Level of the G8 API access log",
+			},
+			"api_log_redact_keys": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Extra JSON keys whose values are masked in the G8 API access log, next to passwords, pre-shared keys and tokens",
+			},
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
+			"ovc_account":           dataSourceOvcAccount(),
+			"ovc_accounts":          dataSourceOvcAccounts(),
 			"ovc_machine":           dataSourceOvcMachine(),
 			"ovc_machines":          dataSourceOvcMachines(),
 			"ovc_cloudspace":        dataSourceOvcCloudSpace(),
 			"ovc_cloudspaces":       dataSourceOvcCloudSpaces(),
+			"ovc_size":              dataSourceOvcSize(),
 			"ovc_sizes":             dataSourceOvcSizes(),
+			"ovc_templates":         dataSourceOvcTemplates(),
 			"ovc_disk":              dataSourceOvcDisk(),
+			"ovc_disks":             dataSourceOvcDisks(),
 			"ovc_port_forwarding":   dataSourceOvcPortForwarding(),
+			"ovc_port_forwardings":  dataSourceOvcPortForwardings(),
 			"ovc_image":             dataSourceOvcImage(),
+			"ovc_ipsec_tunnels":     dataSourceOvcIpsecTunnels(),
 			"ovc_images":            dataSourceOvcImages(),
 			"ovc_external_network":  dataSourceOvcExternalNetwork(),
 			"ovc_external_networks": dataSourceOvcExternalNetworks(),
+			"ovc_location":          dataSourceOvcLocation(),
+			"ovc_locations":         dataSourceOvcLocations(),
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
@@ -63,37 +196,129 @@ func Provider() *schema.Provider {
 			"ovc_cloudspace":      resourceOvcCloudSpace(),
 			"ovc_ipsec":           resourceIpsec(),
 		},
-
-		ConfigureFunc: providerConfigure,
 	}
+	for name, r := range p.DataSourcesMap {
+		withErrorContext(name, r, true)
+	}
+	for name, r := range p.ResourcesMap {
+		withErrorContext(name, r, false)
+	}
+	p.ConfigureFunc = func(d *schema.ResourceData) (interface{}, error) {
+		return providerConfigure(d, p.StopContext())
+	}
+	return p
 }
 
-func providerConfigure(d *schema.ResourceData) (interface{}, error) {
-	var ovcLogger ovc.Logger = nil
-	g8LogFile, found := os.LookupEnv("G8_API_ACCESS_LOG_FILE")
-	if found {
-		logger := logrus.New()
-		switch g8LogLevel, _ := os.LookupEnv("G8_API_ACCESS_LOG_LEVEL"); strings.ToLower(g8LogLevel) {
-		case "debug":
-			logger.SetLevel(logrus.DebugLevel)
-		default:
-			logger.SetLevel(logrus.InfoLevel)
+// providerConfigure builds the meta of the provider, API calls end when stopCtx is done
+func providerConfigure(d *schema.ResourceData, stopCtx context.Context) (interface{}, error) {
+	var redactKeys []string
+	for _, key := range d.Get("api_log_redact_keys").([]interface{}) {
+		redactKeys = append(redactKeys, key.(string))
+	}
+	ovcLogger, err := newAPILogger(d.Get("api_log_file").(string), d.Get("api_log_level").(string), redactKeys)
+	if err != nil {
+		return nil, err
+	}
+	auth := authConfig{
+		identityProvider: d.Get("identity_provider").(string),
+		tokenURL:         d.Get("token_url").(string),
+		usernameClaim:    d.Get("username_claim").(string),
+		clientID:         d.Get("client_id").(string),
+		clientSecret:     d.Get("client_secret").(string),
+		jwt:              d.Get("client_jwt").(string),
+	}
+	for _, scope := range d.Get("scopes").([]interface{}) {
+		auth.scopes = append(auth.scopes, scope.(string))
+	}
+	if auth.usernameClaim == "" {
+		auth.usernameClaim = "username"
+		if auth.identityProvider == identityProviderOIDC {
+			auth.usernameClaim = "preferred_username"
 		}
-		f, err := os.OpenFile(g8LogFile, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
-		if err != nil {
-			logger.Fatalf("error opening file: %v", err)
-			logger.SetOutput(os.Stdout)
-		} else {
-			logger.SetOutput(f)
+	}
+	httpClient := &http.Client{}
+	var tokens tokenSource
+	var access string
+	switch {
+	case d.Get("client_jwt_file").(string) != "":
+		tokens, access, err = newReloadingTokenSource(auth, jwtFileLoader(d.Get("client_jwt_file").(string)), httpClient, ovcLogger)
+	case d.Get("credential_process").(string) != "":
+		tokens, access, err = newReloadingTokenSource(auth, processLoader(d.Get("credential_process").(string)), httpClient, ovcLogger)
+	default:
+		tokens, access, err = newTokenSource(auth, httpClient, ovcLogger)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// durations are checked by validateDuration
+	minBackoff, _ := time.ParseDuration(d.Get("retry_min_backoff").(string))
+	maxBackoff, _ := time.ParseDuration(d.Get("retry_max_backoff").(string))
+	maxPollInterval, _ := time.ParseDuration(d.Get("task_poll_max_interval").(string))
+	if maxBackoff < minBackoff {
+		return nil, fmt.Errorf("retry_max_backoff (%s) must not be smaller than retry_min_backoff (%s)", maxBackoff, minBackoff)
+	}
+	options := apiOptions{
+		concurrency: concurrencyLimits{
+			model:       d.Get("max_concurrent_requests").(int),
+			operational: d.Get("max_concurrent_operational_requests").(int),
+			data:        d.Get("max_concurrent_data_requests").(int),
+			taskPolls:   d.Get("max_concurrent_task_polls").(int),
+		},
+		retry: retryPolicy{
+			maxRetries:      d.Get("max_retries").(int),
+			minBackoff:      minBackoff,
+			maxBackoff:      maxBackoff,
+			maxPollInterval: maxPollInterval,
+		},
+		location: d.Get("location").(string),
+	}
+	serverURL := d.Get("server_url").(string) + "/restmachine"
+	meta := newProviderMeta(newAPIClient(stopCtx, serverURL, tokens, httpClient, ovcLogger, options), access)
+	if options.location != "" {
+		if _, err := meta.location(options.location); err != nil {
+			return nil, err
 		}
-		ovcLogger = ovc.LogrusAdapter{FieldLogger: logger}
 	}
-	config := ovc.Config{
-		URL:          d.Get("server_url").(string),
-		ClientID:     d.Get("client_id").(string),
-		ClientSecret: d.Get("client_secret").(string),
-		JWT:          d.Get("client_jwt").(string),
-		Logger:       ovcLogger,
+	return meta, nil
+}
+
+// newAPILogger returns a logger writing to logFile, or to stderr when no file is set.
+// Secrets and the values of redactKeys are masked in all log entries.
+func newAPILogger(logFile string, level string, redactKeys []string) (ovc.Logger, error) {
+	redactor := newRedactor(redactKeys)
+	if logFile == "" {
+		logger := logrus.New()
+		logger.SetLevel(logrus.InfoLevel)
+		return redactingLogger{logger: ovc.LogrusAdapter{FieldLogger: logger.WithField("source", "OpenvCloud client")}, redactor: redactor}, nil
+	}
+	logLevel, err := logrus.ParseLevel(normalizeLogLevel(level))
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(logFile, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
+	if err != nil {
+		return nil, fmt.Errorf("error opening api_log_file: %v", err)
+	}
+	logger := logrus.New()
+	logger.SetLevel(logLevel)
+	logger.SetOutput(f)
+	return redactingLogger{logger: ovc.LogrusAdapter{FieldLogger: logger}, redactor: redactor}, nil
+}
+
+// normalizeLogLevel makes levels match regardless of case and surrounding spaces,
+// as G8_API_ACCESS_LOG_LEVEL did before it was a provider argument
+func normalizeLogLevel(level string) string {
+	return strings.ToLower(strings.TrimSpace(level))
+}
+
+func validateLogLevel(v interface{}, k string) ([]string, []error) {
+	level := normalizeLogLevel(v.(string))
+	return validation.StringInSlice([]string{"debug", "info", "warn", "error"}, false)(level, k)
+}
+
+func validateDuration(v interface{}, k string) (ws []string, errors []error) {
+	if _, err := time.ParseDuration(v.(string)); err != nil {
+		errors = append(errors, fmt.Errorf("%q: %s", k, err))
 	}
-	return ovc.NewClient(&config)
+	return
 }
@@ -1,6 +1,7 @@
 package ovc
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/hashicorp/terraform/helper/schema"
@@ -26,3 +27,17 @@ func TestProvider(t *testing.T) {
 func TestProvider_impl(t *testing.T) {
 	var _ terraform.ResourceProvider = Provider()
 }
+
+func TestLogLevelIgnoresCase(t *testing.T) {
+	for _, level := range []string{"debug", "DEBUG", "Info", " warn "} {
+		if _, errs := validateLogLevel(level, "api_log_level"); len(errs) != 0 {
+			t.Errorf("%q: %v", level, errs)
+		}
+		if _, err := newAPILogger(filepath.Join(t.TempDir(), "api.log"), level, nil); err != nil {
+			t.Errorf("%q: %v", level, err)
+		}
+	}
+	if _, errs := validateLogLevel("verbose", "api_log_level"); len(errs) == 0 {
+		t.Error("verbose is not a log level")
+	}
+}
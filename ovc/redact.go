@@ -0,0 +1,157 @@
+package ovc
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/gig-tech/ovc-sdk-go/v3/ovc"
+)
+
+// redactedKeys are the JSON keys of secrets in requests and responses of the G8 API,
+// like machine and image passwords, ipsec and NBD pre-shared keys and client credentials
+var redactedKeys = []string{
+	"password", "passwd", "psk", "pskSecret", "secret", "client_secret",
+	"jwt", "client_jwt", "access_token", "refresh_token",
+}
+
+const redactedValue = "***"
+
+var (
+	bearerPattern = regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9._~+/=-]+`)
+	jwtPattern    = regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+)
+
+// redactor masks the values of secret keys in JSON and python dicts, as found in G8 tracebacks,
+// as well as bearer tokens and JWTs
+type redactor struct {
+	keys          map[string]bool
+	doubleQuoted  *regexp.Regexp
+	singleQuoted  *regexp.Regexp
+	unquotedValue *regexp.Regexp
+}
+
+// newRedactor returns a redactor for the default secret keys and the extra keys
+func newRedactor(extraKeys []string) *redactor {
+	keys := make(map[string]bool)
+	var quoted []string
+	for _, key := range append(append([]string{}, redactedKeys...), extraKeys...) {
+		if key == "" || keys[strings.ToLower(key)] {
+			continue
+		}
+		keys[strings.ToLower(key)] = true
+		quoted = append(quoted, regexp.QuoteMeta(key))
+	}
+	names := "(?i:" + strings.Join(quoted, "|") + ")"
+	return &redactor{
+		keys:          keys,
+		doubleQuoted:  regexp.MustCompile(`("` + names + `"\s*:\s*")(?:\\.|[^"\\])*(")`),
+		singleQuoted:  regexp.MustCompile(`('` + names + `'\s*:\s*[u]?')(?:\\.|[^'\\])*(')`),
+		unquotedValue: regexp.MustCompile(`(["']` + names + `["']\s*:\s*)(-?[0-9][0-9.eE+-]*|true|false)`),
+	}
+}
+
+// redact returns text with all secrets masked
+func (r *redactor) redact(text string) string {
+	text = r.doubleQuoted.ReplaceAllString(text, "${1}"+redactedValue+"${2}")
+	text = r.singleQuoted.ReplaceAllString(text, "${1}"+redactedValue+"${2}")
+	text = r.unquotedValue.ReplaceAllString(text, `${1}"`+redactedValue+`"`)
+	text = bearerPattern.ReplaceAllString(text, "${1}"+redactedValue)
+	return jwtPattern.ReplaceAllString(text, redactedValue)
+}
+
+// redactingLogger is the API access logger, it masks secrets in all messages and fields
+type redactingLogger struct {
+	logger   ovc.Logger
+	redactor *redactor
+}
+
+func (l redactingLogger) with(logger ovc.Logger) ovc.Logger {
+	return redactingLogger{logger: logger, redactor: l.redactor}
+}
+
+func (l redactingLogger) field(key string, value interface{}) interface{} {
+	if l.redactor.keys[strings.ToLower(key)] {
+		return redactedValue
+	}
+	if s, ok := value.(string); ok {
+		return l.redactor.redact(s)
+	}
+	return value
+}
+
+func (l redactingLogger) WithField(key string, value interface{}) ovc.Logger {
+	return l.with(l.logger.WithField(key, l.field(key, value)))
+}
+
+func (l redactingLogger) WithFields(fields map[string]interface{}) ovc.Logger {
+	redacted := make(map[string]interface{}, len(fields))
+	for key, value := range fields {
+		redacted[key] = l.field(key, value)
+	}
+	return l.with(l.logger.WithFields(redacted))
+}
+
+func (l redactingLogger) WithError(err error) ovc.Logger {
+	if err != nil {
+		err = errors.New(l.redactor.redact(err.Error()))
+	}
+	return l.with(l.logger.WithError(err))
+}
+
+func (l redactingLogger) sprintf(format string, args []interface{}) string {
+	return l.redactor.redact(fmt.Sprintf(format, args...))
+}
+
+func (l redactingLogger) sprint(args []interface{}) string {
+	return l.redactor.redact(fmt.Sprint(args...))
+}
+
+func (l redactingLogger) Debugf(format string, args ...interface{}) {
+	l.logger.Debug(l.sprintf(format, args))
+}
+
+func (l redactingLogger) Infof(format string, args ...interface{}) {
+	l.logger.Info(l.sprintf(format, args))
+}
+
+func (l redactingLogger) Warnf(format string, args ...interface{}) {
+	l.logger.Warn(l.sprintf(format, args))
+}
+
+func (l redactingLogger) Errorf(format string, args ...interface{}) {
+	l.logger.Error(l.sprintf(format, args))
+}
+
+func (l redactingLogger) Fatalf(format string, args ...interface{}) {
+	l.logger.Fatal(l.sprintf(format, args))
+}
+
+func (l redactingLogger) Panicf(format string, args ...interface{}) {
+	l.logger.Panic(l.sprintf(format, args))
+}
+
+func (l redactingLogger) Debug(args ...interface{}) {
+	l.logger.Debug(l.sprint(args))
+}
+
+func (l redactingLogger) Info(args ...interface{}) {
+	l.logger.Info(l.sprint(args))
+}
+
+func (l redactingLogger) Warn(args ...interface{}) {
+	l.logger.Warn(l.sprint(args))
+}
+
+func (l redactingLogger) Error(args ...interface{}) {
+	l.logger.Error(l.sprint(args))
+}
+
+func (l redactingLogger) Fatal(args ...interface{}) {
+	l.logger.Fatal(l.sprint(args))
+}
+
+func (l redactingLogger) Panic(args ...interface{}) {
+	l.logger.Panic(l.sprint(args))
+}
@@ -0,0 +1,146 @@
+package ovc
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/gig-tech/ovc-sdk-go/v3/ovc"
+)
+
+// imageStatusCreated is the status of images that machines can be deployed from
+const imageStatusCreated = "CREATED"
+
+// sizeNamePattern matches size names like 4vcpu-8gb
+var sizeNamePattern = regexp.MustCompile(`^(\d+)vcpus?-(\d+)gb$`)
+
+// resolveAccountID returns the ID of the account with the given name
+func resolveAccountID(client *ovc.Client, name string) (int, error) {
+	accounts, err := client.Accounts.List()
+	if err != nil {
+		return 0, err
+	}
+	for _, account := range *accounts {
+		if account.Name == name {
+			return account.ID, nil
+		}
+	}
+	return 0, fmt.Errorf("account %q: %w", name, ovc.ErrNotFound)
+}
+
+// resolveAccountName returns the name of the account with the given ID
+func resolveAccountName(client *ovc.Client, id int) (string, error) {
+	accounts, err := client.Accounts.List()
+	if err != nil {
+		return "", err
+	}
+	for _, account := range *accounts {
+		if account.ID == id {
+			return account.Name, nil
+		}
+	}
+	return "", fmt.Errorf("account %d: %w", id, ovc.ErrNotFound)
+}
+
+// resolveImageID returns the ID of the usable image with the given name available in the account of the cloudspace
+func resolveImageID(client *ovc.Client, cloudspaceID int, name string) (int, error) {
+	cloudspace, err := client.CloudSpaces.Get(cloudspaceID)
+	if err != nil {
+		return 0, err
+	}
+	images, err := client.Images.List(cloudspace.AccountID)
+	if err != nil {
+		return 0, err
+	}
+	return selectImage(*images, name)
+}
+
+// selectImage returns the ID of the only image with the given name that is ready to deploy machines from.
+// Images that are disabled, being deleted or still being created are skipped.
+func selectImage(images []ovc.ImageInfo, name string) (int, error) {
+	var usable, candidates []string
+	imageID := 0
+	for _, image := range images {
+		if image.Name != name {
+			continue
+		}
+		candidates = append(candidates, fmt.Sprintf("%d (%s)", image.ID, image.Status))
+		if image.Status == imageStatusCreated {
+			usable = append(usable, strconv.Itoa(image.ID))
+			imageID = image.ID
+		}
+	}
+	switch {
+	case len(candidates) == 0:
+		return 0, fmt.Errorf("image %q: %w", name, ovc.ErrNotFound)
+	case len(usable) == 0:
+		return 0, fmt.Errorf("image %q has no usable image, the images with this name are: %s", name, strings.Join(candidates, ", "))
+	case len(usable) > 1:
+		return 0, fmt.Errorf("image %q is ambiguous, use image_id to pick one of the images %s", name, strings.Join(usable, ", "))
+	}
+	return imageID, nil
+}
+
+// resolveSizeID returns the ID of the size allowed in the cloudspace by its name,
+// or by vcpus and memory for names like 4vcpu-8gb
+func resolveSizeID(client *ovc.Client, cloudspaceID int, name string) (int, error) {
+	sizes, err := client.Sizes.List(cloudspaceID)
+	if err != nil {
+		return 0, err
+	}
+	for _, size := range *sizes {
+		if size.Name == name {
+			return size.ID, nil
+		}
+	}
+	if match := sizeNamePattern.FindStringSubmatch(name); match != nil {
+		vcpus, _ := strconv.Atoi(match[1])
+		memory, _ := strconv.Atoi(match[2])
+		for _, size := range *sizes {
+			if size.Vcpus == vcpus && size.Memory == memory*1024 {
+				return size.ID, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("size %q in cloudspace %d: %w", name, cloudspaceID, ovc.ErrNotFound)
+}
+
+// resolveExternalNetworkID returns the ID of the external network with the given name
+// available in the account of the cloudspace
+func resolveExternalNetworkID(client *ovc.Client, cloudspaceID int, name string) (int, error) {
+	cloudspace, err := client.CloudSpaces.Get(cloudspaceID)
+	if err != nil {
+		return 0, err
+	}
+	externalNetworks, err := client.ExternalNetworks.List(cloudspace.AccountID)
+	if err != nil {
+		return 0, err
+	}
+	for _, externalNetwork := range *externalNetworks {
+		if externalNetwork.Name == name && (externalNetwork.AccountID == cloudspace.AccountID || externalNetwork.AccountID == 0) {
+			return externalNetwork.ID, nil
+		}
+	}
+	return 0, fmt.Errorf("external network %q: %w", name, ovc.ErrNotFound)
+}
+
+// resolveInterfaceNetworks sets the network_id of the interfaces that reference their external network by name
+func resolveInterfaceNetworks(client *ovc.Client, cloudspaceID int, nics []interface{}) error {
+	for _, nici := range nics {
+		if nici == nil {
+			continue
+		}
+		nic := nici.(map[string]interface{})
+		name, _ := nic["external_network_name"].(string)
+		if name == "" {
+			continue
+		}
+		networkID, err := resolveExternalNetworkID(client, cloudspaceID, name)
+		if err != nil {
+			return err
+		}
+		nic["network_id"] = networkID
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package ovc
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/gig-tech/ovc-sdk-go/v3/ovc"
+)
+
+func TestSelectImage(t *testing.T) {
+	images := []ovc.ImageInfo{
+		{ID: 1, Name: "ubuntu", Status: "CREATED"},
+		{ID: 5, Name: "ubuntu", Status: "DISABLED"},
+		{ID: 6, Name: "ubuntu", Status: "CREATING"},
+		{ID: 7, Name: "debian", Status: "DELETING"},
+		{ID: 8, Name: "centos", Status: "CREATED"},
+		{ID: 9, Name: "centos", Status: "CREATED"},
+	}
+	if id, err := selectImage(images, "ubuntu"); err != nil || id != 1 {
+		t.Errorf("ubuntu: id = %d, err = %v, want the created image 1", id, err)
+	}
+	if _, err := selectImage(images, "debian"); err == nil || !strings.Contains(err.Error(), "7 (DELETING)") {
+		t.Errorf("debian: expected an error listing the unusable image, got %v", err)
+	}
+	if _, err := selectImage(images, "centos"); err == nil || !strings.Contains(err.Error(), "8, 9") {
+		t.Errorf("centos: expected an error listing both images, got %v", err)
+	}
+	if _, err := selectImage(images, "windows"); !errors.Is(err, ovc.ErrNotFound) {
+		t.Errorf("windows: expected not found, got %v", err)
+	}
+}
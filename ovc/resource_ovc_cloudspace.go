@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"strconv"
+	"time"
 
 	"github.com/gig-tech/ovc-sdk-go/v3/ovc"
 	"github.com/hashicorp/terraform/helper/resource"
@@ -16,7 +17,11 @@ func resourceOvcCloudSpace() *schema.Resource {
 		Read:   resourceOvcCloudSpaceRead,
 		Update: resourceOvcCloudSpaceUpdate,
 		Delete: resourceOvcCloudSpaceDelete,
-		Exists: resourceOvcCloudspaceExists,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
 
 		CustomizeDiff: func(diff *schema.ResourceDiff, v interface{}) error {
 			if diff.Id() != "" && diff.HasChange("private_network") {
@@ -28,6 +33,23 @@ func resourceOvcCloudSpace() *schema.Resource {
 			if diff.Id() != "" && diff.HasChange("mode") {
 				return fmt.Errorf("Cannot change Mode on existing cloudspace")
 			}
+			if location, ok := diff.GetOk("location"); ok && diff.Id() == "" {
+				if _, err := v.(*providerMeta).location(location.(string)); err != nil {
+					return err
+				}
+			}
+			if diff.Id() != "" && diff.HasChange("location") {
+				// the location can be configured by name, the G8 returns its code
+				old, new := diff.GetChange("location")
+				if location, err := v.(*providerMeta).location(new.(string)); err == nil && location.Code == old.(string) {
+					if err := diff.Clear("location"); err != nil {
+						return err
+					}
+				}
+			}
+			if diff.Id() == "" {
+				return resolveCloudSpaceAccount(diff, v.(*providerMeta).client)
+			}
 			return nil
 		},
 		Schema: map[string]*schema.Schema{
@@ -36,8 +58,16 @@ func resourceOvcCloudSpace() *schema.Resource {
 				Required: true,
 			},
 			"account": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:          schema.TypeString,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"account_id"},
+			},
+			"account_id": {
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"account"},
 			},
 			"external_network_ip": {
 				Type:     schema.TypeString,
@@ -79,7 +109,9 @@ func resourceOvcCloudSpace() *schema.Resource {
 			},
 			"location": {
 				Type:     schema.TypeString,
+				Optional: true,
 				Computed: true,
+				ForceNew: true,
 			},
 			"resource_limits": {
 				Type:     schema.TypeMap,
@@ -118,22 +150,33 @@ func resourceOvcCloudSpace() *schema.Resource {
 	}
 }
 
-func resourceOvcCloudspaceExists(d *schema.ResourceData, m interface{}) (bool, error) {
-	client := m.(*ovc.Client)
-	cloudspaceID, err := strconv.Atoi(d.Id())
-	if err != nil {
-		log.Printf("Failed to convert %s into cloudspaceID in resourceOvcCloudspaceExists", d.Id())
-		return false, err
+// resolveCloudSpaceAccount sets the account ID from the account name or the other way around when planning a new cloudspace
+func resolveCloudSpaceAccount(diff *schema.ResourceDiff, client *ovc.Client) error {
+	if account := diff.Get("account").(string); account != "" {
+		accountID, err := resolveAccountID(client, account)
+		if err != nil {
+			return err
+		}
+		return diff.SetNew("account_id", accountID)
 	}
-	cloudspace, err := client.CloudSpaces.Get(cloudspaceID)
-	if err != nil || cloudspace.Status == "DESTROYED" {
-		return false, err
+	if accountID, ok := diff.GetOk("account_id"); ok {
+		account, err := resolveAccountName(client, accountID.(int))
+		if err != nil {
+			return err
+		}
+		return diff.SetNew("account", account)
+	}
+	if diff.NewValueKnown("account") && diff.NewValueKnown("account_id") {
+		return fmt.Errorf("One of account or account_id must be set")
 	}
-	return true, nil
+	return nil
 }
 
 func resourceOvcCloudSpaceRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(*ovc.Client)
+	if ok, err := resumeCreate(d, m.(*providerMeta).api, idFromResult); !ok || err != nil {
+		return err
+	}
+	client := m.(*providerMeta).client
 	cloudspaceID, err := strconv.Atoi(d.Id())
 	if err != nil {
 		log.Printf("Failed to convert %s into cloudspaceID in resourceOvcCloudSpaceRead", d.Id())
@@ -141,10 +184,15 @@ func resourceOvcCloudSpaceRead(d *schema.ResourceData, m interface{}) error {
 	}
 	cloudspace, err := client.CloudSpaces.Get(cloudspaceID)
 	if err != nil {
+		if isNotFound(err) {
+			log.Printf("[WARN] Cloudspace %s not found, removing it from state", d.Id())
+			d.SetId("")
+			return nil
+		}
 		return err
 	}
 	if cloudspace.Status == "DESTROYED" {
-		log.Println("cloudspace destroyed baby")
+		log.Printf("[WARN] Cloudspace %s is destroyed, removing it from state", d.Id())
 		d.SetId("")
 		return nil
 	}
@@ -159,22 +207,30 @@ func resourceOvcCloudSpaceRead(d *schema.ResourceData, m interface{}) error {
 	d.Set("description", cloudspace.Description)
 	d.Set("external_network_ip", cloudspace.Externalnetworkip)
 	d.Set("location", cloudspace.Location)
+	d.Set("account_id", cloudspace.AccountID)
 	return nil
 
 }
 
 func resourceOvcCloudSpaceCreate(d *schema.ResourceData, m interface{}) error {
-	client := m.(*ovc.Client)
-	account := d.Get("account").(string)
-	accountID, err := client.Accounts.GetIDByName(account)
+	client, _, cancel := m.(*providerMeta).withTimeout(d.Timeout(schema.TimeoutCreate))
+	defer cancel()
+	accountID := d.Get("account_id").(int)
+	if accountID == 0 {
+		var err error
+		if accountID, err = resolveAccountID(client, d.Get("account").(string)); err != nil {
+			return err
+		}
+		d.Set("account_id", accountID)
+	}
+	location, err := m.(*providerMeta).location(d.Get("location").(string))
 	if err != nil {
 		return err
 	}
-	location := client.GetLocation()
 	cloudSpaceConfig := ovc.CloudSpaceConfig{
 		Access:                 client.Access,
 		AccountID:              accountID,
-		Location:               location,
+		Location:               location.Code,
 		Name:                   d.Get("name").(string),
 		MaxMemoryCapacity:      -1,
 		MaxCPUCapacity:         -1,
@@ -226,12 +282,14 @@ func resourceOvcCloudSpaceCreate(d *schema.ResourceData, m interface{}) error {
 	}
 	cloudspaceID, err := client.CloudSpaces.Create(&cloudSpaceConfig)
 	if err != nil {
-		return err
+		return recordInterruptedCreate(d, err)
 	}
 	d.SetId(strconv.Itoa(cloudspaceID))
 	return resource.Retry(d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
-		cloudspace, _ := client.CloudSpaces.Get(cloudspaceID)
-
+		cloudspace, err := client.CloudSpaces.Get(cloudspaceID)
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
 		if cloudspace.Status != "DEPLOYED" {
 			log.Print("[DEBUG] Cloudspace is still deploying")
 			return resource.RetryableError(fmt.Errorf("Cloudspace is in state: %s", cloudspace.Status))
@@ -241,9 +299,18 @@ func resourceOvcCloudSpaceCreate(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceOvcCloudSpaceUpdate(d *schema.ResourceData, m interface{}) error {
-	client := m.(*ovc.Client)
+	cloudSpaceID, err := strconv.Atoi(d.Id())
+	if err != nil {
+		return err
+	}
+	client, ctx, cancel := m.(*providerMeta).withTimeout(d.Timeout(schema.TimeoutUpdate))
+	defer cancel()
+	unlock, err := m.(*providerMeta).lock(ctx, cloudspaceKey(cloudSpaceID))
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	if d.HasChange("resource_limits") {
-		cloudSpaceID, _ := strconv.Atoi(d.Id())
 		cloudSpaceConfig := ovc.CloudSpaceConfig{
 			MaxMemoryCapacity:      -1,
 			MaxCPUCapacity:         -1,
@@ -302,13 +369,22 @@ func resourceOvcCloudSpaceUpdate(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceOvcCloudSpaceDelete(d *schema.ResourceData, m interface{}) error {
-	client := m.(*ovc.Client)
+	if ok, err := resumeBeforeDelete(d, m.(*providerMeta).api, idFromResult); !ok || err != nil {
+		return err
+	}
+	client, ctx, cancel := m.(*providerMeta).withTimeout(d.Timeout(schema.TimeoutDelete))
+	defer cancel()
 	cloudSpaceConfig := ovc.CloudSpaceDeleteConfig{}
 	cloudSpaceID, err := strconv.Atoi(d.Id())
 	cloudSpaceConfig.CloudSpaceID = cloudSpaceID
 	if err != nil {
 		return err
 	}
+	unlock, err := m.(*providerMeta).lock(ctx, cloudspaceKey(cloudSpaceID))
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	cloudSpaceConfig.Permanently = true
 	err = client.CloudSpaces.Delete(&cloudSpaceConfig)
 	return err
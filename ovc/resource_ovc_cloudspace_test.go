@@ -0,0 +1,35 @@
+package ovc
+
+import (
+	"testing"
+
+	"github.com/gig-tech/ovc-sdk-go/v3/ovc"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestCloudSpaceLocationByName(t *testing.T) {
+	meta := &providerMeta{api: &apiClient{locations: &locationResolver{locations: ovc.LocationList{
+		{Name: "Lugano", Code: "ch-lug-1"},
+		{Name: "Geneva", Code: "ch-gen-1"},
+	}}}}
+	state := &terraform.InstanceState{
+		ID: "1",
+		Attributes: map[string]string{
+			"id":       "1",
+			"name":     "cs",
+			"mode":     "public",
+			"type":     "vgw",
+			"location": "ch-lug-1",
+		},
+	}
+	for location, replace := range map[string]bool{"Lugano": false, "ch-lug-1": false, "Geneva": true} {
+		config := terraform.NewResourceConfigRaw(map[string]interface{}{"name": "cs", "location": location})
+		diff, err := resourceOvcCloudSpace().Diff(state, config, meta)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff.RequiresNew() != replace {
+			t.Errorf("location %s: replace = %v, want %v", location, diff.RequiresNew(), replace)
+		}
+	}
+}
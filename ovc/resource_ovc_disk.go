@@ -1,7 +1,9 @@
 package ovc
 
 import (
+	"log"
 	"strconv"
+	"time"
 
 	"github.com/gig-tech/ovc-sdk-go/v3/ovc"
 	"github.com/hashicorp/terraform/helper/schema"
@@ -13,7 +15,11 @@ func resourceOvcDisk() *schema.Resource {
 		Read:   resourceOvcDiskRead,
 		Update: resourceOvcDiskUpdate,
 		Delete: resourceOvcDiskDelete,
-		Exists: resourceOvcDiskExists,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"machine_id": {
@@ -48,27 +54,26 @@ func resourceOvcDisk() *schema.Resource {
 	}
 }
 
-func resourceOvcDiskExists(d *schema.ResourceData, m interface{}) (bool, error) {
-	client := m.(*ovc.Client)
+func resourceOvcDiskRead(d *schema.ResourceData, m interface{}) error {
+	if ok, err := resumeCreate(d, m.(*providerMeta).api, idFromResult); !ok || err != nil {
+		return err
+	}
+	client := m.(*providerMeta).client
 	diskID, err := strconv.Atoi(d.Id())
 	if err != nil {
-		return false, nil
+		return err
 	}
 	disk, err := client.Disks.Get(diskID)
-	if err != nil || disk.Status == "DESTROYED" {
-		return false, nil
-	}
-	return true, nil
-}
-
-func resourceOvcDiskRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(*ovc.Client)
-	diskID, err := strconv.Atoi(d.Id())
 	if err != nil {
-		return nil
+		if isNotFound(err) {
+			log.Printf("[WARN] Disk %s not found, removing it from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return err
 	}
-	_, err = client.Disks.Get(diskID)
-	if err != nil {
+	if disk.Status == "DESTROYED" {
+		log.Printf("[WARN] Disk %s is destroyed, removing it from state", d.Id())
 		d.SetId("")
 		return nil
 	}
@@ -77,7 +82,13 @@ func resourceOvcDiskRead(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceOvcDiskCreate(d *schema.ResourceData, m interface{}) error {
-	client := m.(*ovc.Client)
+	client, ctx, cancel := m.(*providerMeta).withTimeout(d.Timeout(schema.TimeoutCreate))
+	defer cancel()
+	unlock, err := m.(*providerMeta).lock(ctx, machineKey(d.Get("machine_id").(int)))
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	diskConfig := ovc.DiskConfig{}
 	diskConfig.MachineID = d.Get("machine_id").(int)
 	diskConfig.DiskName = d.Get("disk_name").(string)
@@ -88,7 +99,7 @@ func resourceOvcDiskCreate(d *schema.ResourceData, m interface{}) error {
 	diskConfig.IOPS = d.Get("iops").(int)
 	diskID, err := client.Disks.CreateAndAttach(&diskConfig)
 	if err != nil {
-		return err
+		return recordInterruptedCreate(d, err)
 	}
 	d.SetId(strconv.Itoa(diskID))
 
@@ -96,7 +107,13 @@ func resourceOvcDiskCreate(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceOvcDiskUpdate(d *schema.ResourceData, m interface{}) error {
-	client := m.(*ovc.Client)
+	client, ctx, cancel := m.(*providerMeta).withTimeout(d.Timeout(schema.TimeoutUpdate))
+	defer cancel()
+	unlock, err := m.(*providerMeta).lock(ctx, machineKey(d.Get("machine_id").(int)))
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	diskConfig := ovc.DiskConfig{}
 	update := false
 	diskID, err := strconv.Atoi(d.Id())
@@ -126,9 +143,16 @@ func resourceOvcDiskUpdate(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceOvcDiskDelete(d *schema.ResourceData, m interface{}) error {
-	defer ovc.ReleaseLock(d.Get("machine_id").(int))
-	ovc.GetLock(d.Get("machine_id").(int))
-	client := m.(*ovc.Client)
+	if ok, err := resumeBeforeDelete(d, m.(*providerMeta).api, idFromResult); !ok || err != nil {
+		return err
+	}
+	client, ctx, cancel := m.(*providerMeta).withTimeout(d.Timeout(schema.TimeoutDelete))
+	defer cancel()
+	unlock, err := m.(*providerMeta).lock(ctx, machineKey(d.Get("machine_id").(int)))
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	diskConfig := ovc.DiskDeleteConfig{}
 	diskID, err := strconv.Atoi(d.Id())
 	diskConfig.DiskID = diskID
@@ -1,7 +1,10 @@
 package ovc
 
 import (
+	"fmt"
+	"log"
 	"strconv"
+	"time"
 
 	"github.com/gig-tech/ovc-sdk-go/v3/ovc"
 	"github.com/hashicorp/terraform/helper/schema"
@@ -13,7 +16,11 @@ func resourcePortForwarding() *schema.Resource {
 		Read:   resourcePortForwardingRead,
 		Update: resourcePortForwardingUpdate,
 		Delete: resourcePortForwardingDelete,
-		Exists: resourcePortForwardingExists,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"cloudspace_id": {
@@ -52,43 +59,51 @@ func resourcePortForwarding() *schema.Resource {
 	}
 }
 
-func resourcePortForwardingExists(d *schema.ResourceData, m interface{}) (bool, error) {
-	client := m.(*ovc.Client)
-	publicPort := d.Get("public_port").(int)
-	cloudspaceID := d.Get("cloudspace_id").(int)
-	machineID := d.Get("machine_id").(int)
-	portForwardingConfig := &ovc.PortForwardingConfig{
-		MachineID:    machineID,
-		CloudspaceID: cloudspaceID,
-		PublicPort:   publicPort,
-	}
-	_, err := client.Portforwards.Get(portForwardingConfig)
-	if err != nil {
-		return false, nil
-	}
-	return true, nil
-}
-
 func resourcePortForwardingRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(*ovc.Client)
+	if ok, err := resumeCreate(d, m.(*providerMeta).api, forwardingFromResult); !ok || err != nil {
+		return err
+	}
+	client := m.(*providerMeta).client
 	portForwardingConfig := ovc.PortForwardingConfig{}
 	portForwardingConfig.CloudspaceID = d.Get("cloudspace_id").(int)
 	portForwardingConfig.MachineID = d.Get("machine_id").(int)
 	portForwardingList, err := client.Portforwards.List(&portForwardingConfig)
 	if err != nil {
+		if isNotFound(err) {
+			log.Printf("[WARN] Cloudspace of port forward %s not found, removing it from state", d.Id())
+			d.SetId("")
+			return nil
+		}
 		return err
 	}
+	publicIP := d.Get("public_ip").(string)
+	publicPort := strconv.Itoa(d.Get("public_port").(int))
 	for _, pf := range *portForwardingList {
-		publicPort := d.Get("public_port").(int)
-		if pf.PublicPort == strconv.Itoa(publicPort) {
+		if pf.PublicIP == publicIP && pf.PublicPort == publicPort {
 			d.SetId(strconv.Itoa(pf.ID))
+			return nil
 		}
 	}
+	log.Printf("[WARN] Port forward %s not found, removing it from state", d.Id())
+	d.SetId("")
+	return nil
+}
+
+// forwardingFromResult resumes the create of a port forward, its task does not return the ID,
+// the port forward is looked up by its public IP and port
+func forwardingFromResult(d *schema.ResourceData, result []byte) error {
+	d.SetId(fmt.Sprintf("%s:%d", d.Get("public_ip").(string), d.Get("public_port").(int)))
 	return nil
 }
 
 func resourcePortForwardingCreate(d *schema.ResourceData, m interface{}) error {
-	client := m.(*ovc.Client)
+	client, ctx, cancel := m.(*providerMeta).withTimeout(d.Timeout(schema.TimeoutCreate))
+	defer cancel()
+	unlock, err := m.(*providerMeta).lock(ctx, cloudspaceKey(d.Get("cloudspace_id").(int)))
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	portForwardingConfig := ovc.PortForwardingConfig{}
 	portForwardingConfig.CloudspaceID = d.Get("cloudspace_id").(int)
 	portForwardingConfig.PublicIP = d.Get("public_ip").(string)
@@ -98,7 +113,9 @@ func resourcePortForwardingCreate(d *schema.ResourceData, m interface{}) error {
 	portForwardingConfig.Protocol = d.Get("protocol").(string)
 	publicPort, err := client.Portforwards.Create(&portForwardingConfig)
 	if err != nil {
-		return err
+		// keep the picked public port, the port forward is found by it once the task completed
+		d.Set("public_port", portForwardingConfig.PublicPort)
+		return recordInterruptedCreate(d, err)
 	}
 	d.Set("public_port", publicPort)
 	return resourcePortForwardingRead(d, m)
@@ -106,7 +123,13 @@ func resourcePortForwardingCreate(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourcePortForwardingUpdate(d *schema.ResourceData, m interface{}) error {
-	client := m.(*ovc.Client)
+	client, ctx, cancel := m.(*providerMeta).withTimeout(d.Timeout(schema.TimeoutUpdate))
+	defer cancel()
+	unlock, err := m.(*providerMeta).lock(ctx, cloudspaceKey(d.Get("cloudspace_id").(int)))
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	portForwardingConfig := ovc.PortForwardingConfig{}
 	portForwardingConfig.CloudspaceID = d.Get("cloudspace_id").(int)
 	needForUpdate := false
@@ -153,12 +176,21 @@ func resourcePortForwardingUpdate(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourcePortForwardingDelete(d *schema.ResourceData, m interface{}) error {
-	client := m.(*ovc.Client)
+	if ok, err := resumeBeforeDelete(d, m.(*providerMeta).api, forwardingFromResult); !ok || err != nil {
+		return err
+	}
+	client, ctx, cancel := m.(*providerMeta).withTimeout(d.Timeout(schema.TimeoutDelete))
+	defer cancel()
+	unlock, err := m.(*providerMeta).lock(ctx, cloudspaceKey(d.Get("cloudspace_id").(int)))
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	portForwardingConfig := ovc.PortForwardingConfig{}
 	portForwardingConfig.CloudspaceID = d.Get("cloudspace_id").(int)
 	portForwardingConfig.PublicIP = d.Get("public_ip").(string)
 	portForwardingConfig.PublicPort = d.Get("public_port").(int)
 	portForwardingConfig.Protocol = d.Get("protocol").(string)
-	err := client.Portforwards.Delete(&portForwardingConfig)
+	err = client.Portforwards.Delete(&portForwardingConfig)
 	return err
 }
@@ -1,7 +1,10 @@
 package ovc
 
 import (
+	"encoding/json"
 	"fmt"
+	"log"
+	"time"
 
 	"github.com/gig-tech/ovc-sdk-go/v3/ovc"
 	"github.com/hashicorp/terraform/helper/schema"
@@ -13,6 +16,10 @@ func resourceIpsec() *schema.Resource {
 		Read:   resourceIpsecRead,
 		Delete: resourceIpsecDelete,
 		Update: resourceIpsecUpdate,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"cloudspace_id": {
@@ -41,26 +48,54 @@ func resourceIpsec() *schema.Resource {
 }
 
 func resourceIpsecRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(*ovc.Client)
+	if ok, err := resumeCreate(d, m.(*providerMeta).api, ipsecFromResult); !ok || err != nil {
+		return err
+	}
+	client := m.(*providerMeta).client
 	ipsecConfig := ovc.IpsecConfig{}
 	ipsecConfig.CloudspaceID = d.Get("cloudspace_id").(int)
 	tunnelList, err := client.Ipsec.List(&ipsecConfig)
 	if err != nil {
+		if isNotFound(err) {
+			log.Printf("[WARN] Cloudspace of ipsec tunnel %s not found, removing it from state", d.Id())
+			d.SetId("")
+			return nil
+		}
 		return err
 	}
+	remotePublicIP := d.Get("remote_public_ip").(string)
+	remotePrivateNetwork := d.Get("remote_private_network").(string)
 	for _, tunnel := range *tunnelList {
-		remotePublicIP := d.Get("remote_public_ip").(string)
-		remotePrivateNetwork := d.Get("remote_private_network").(string)
 		if tunnel.RemoteAddr == remotePublicIP && tunnel.RemotePrivateNetwork == remotePrivateNetwork {
-			tunnelID := fmt.Sprintf("%s:%s", remotePublicIP, remotePrivateNetwork)
-			d.SetId(tunnelID)
+			d.SetId(fmt.Sprintf("%s:%s", remotePublicIP, remotePrivateNetwork))
+			return nil
 		}
 	}
+	log.Printf("[WARN] Ipsec tunnel %s not found, removing it from state", d.Id())
+	d.SetId("")
+	return nil
+}
+
+// ipsecFromResult keeps the pre-shared key the create task returned, the tunnel is looked up
+// by its remote address and network
+func ipsecFromResult(d *schema.ResourceData, result []byte) error {
+	var psk string
+	if err := json.Unmarshal(result, &psk); err != nil {
+		return err
+	}
+	d.Set("psk", psk)
+	d.SetId(fmt.Sprintf("%s:%s", d.Get("remote_public_ip").(string), d.Get("remote_private_network").(string)))
 	return nil
 }
 
 func resourceIpsecCreate(d *schema.ResourceData, m interface{}) error {
-	client := m.(*ovc.Client)
+	client, ctx, cancel := m.(*providerMeta).withTimeout(d.Timeout(schema.TimeoutCreate))
+	defer cancel()
+	unlock, err := m.(*providerMeta).lock(ctx, cloudspaceKey(d.Get("cloudspace_id").(int)))
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	ipsecConfig := ovc.IpsecConfig{}
 	ipsecConfig.CloudspaceID = d.Get("cloudspace_id").(int)
 	ipsecConfig.RemotePublicAddr = d.Get("remote_public_ip").(string)
@@ -68,7 +103,7 @@ func resourceIpsecCreate(d *schema.ResourceData, m interface{}) error {
 	ipsecConfig.PskSecret = d.Get("psk").(string)
 	PskSecret, err := client.Ipsec.Create(&ipsecConfig)
 	if err != nil {
-		return err
+		return recordInterruptedCreate(d, err)
 	}
 	d.Set("psk", PskSecret)
 	return resourceIpsecRead(d, m)
@@ -76,12 +111,21 @@ func resourceIpsecCreate(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceIpsecDelete(d *schema.ResourceData, m interface{}) error {
-	client := m.(*ovc.Client)
+	if ok, err := resumeBeforeDelete(d, m.(*providerMeta).api, ipsecFromResult); !ok || err != nil {
+		return err
+	}
+	client, ctx, cancel := m.(*providerMeta).withTimeout(d.Timeout(schema.TimeoutDelete))
+	defer cancel()
+	unlock, err := m.(*providerMeta).lock(ctx, cloudspaceKey(d.Get("cloudspace_id").(int)))
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	ipsecConfig := ovc.IpsecConfig{}
 	ipsecConfig.CloudspaceID = d.Get("cloudspace_id").(int)
 	ipsecConfig.RemotePublicAddr = d.Get("remote_public_ip").(string)
 	ipsecConfig.RemotePrivateNetwork = d.Get("remote_private_network").(string)
-	err := client.Ipsec.Delete(&ipsecConfig)
+	err = client.Ipsec.Delete(&ipsecConfig)
 	return err
 }
 
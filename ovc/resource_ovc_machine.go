@@ -5,6 +5,8 @@ import (
 	"log"
 	"net"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gig-tech/ovc-sdk-go/v3/ovc"
 	"github.com/hashicorp/terraform/helper/schema"
@@ -16,19 +18,17 @@ func resourceOvcMachine() *schema.Resource {
 		Read:   resourceOvcMachineRead,
 		Update: resourceOvcMachineUpdate,
 		Delete: resourceOvcMachineDelete,
-		Exists: resourceOvcMachineExists,
 		Importer: &schema.ResourceImporter{
 			State: schema.ImportStatePassthrough,
 		},
-
-		CustomizeDiff: func(diff *schema.ResourceDiff, v interface{}) error {
-			if diff.Id() != "" && diff.HasChange("image_id") {
-				return fmt.Errorf("Cannot change Image ID on existing machine")
-			}
-
-			return nil
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
 		},
 
+		CustomizeDiff: resourceOvcMachineCustomizeDiff,
+
 		Schema: map[string]*schema.Schema{
 			"cloudspace_id": {
 				Type:     schema.TypeInt,
@@ -45,24 +45,36 @@ func resourceOvcMachine() *schema.Resource {
 			"size_id": {
 				Type:          schema.TypeInt,
 				Optional:      true,
-				ConflictsWith: []string{"memory", "vcpus"},
+				ConflictsWith: []string{"memory", "vcpus", "size"},
 				Computed:      true,
 			},
+			"size": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"memory", "vcpus", "size_id"},
+			},
 			"memory": {
 				Type:          schema.TypeInt,
 				Optional:      true,
-				ConflictsWith: []string{"size_id"},
+				ConflictsWith: []string{"size_id", "size"},
 				Computed:      true,
 			},
 			"vcpus": {
 				Type:          schema.TypeInt,
 				Optional:      true,
-				ConflictsWith: []string{"size_id"},
+				ConflictsWith: []string{"size_id", "size"},
 				Computed:      true,
 			},
 			"image_id": {
-				Type:     schema.TypeInt,
-				Required: true,
+				Type:          schema.TypeInt,
+				Optional:      true,
+				Computed:      true,
+				ConflictsWith: []string{"image_name"},
+			},
+			"image_name": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"image_id"},
 			},
 			"disk_id": {
 				Type:     schema.TypeInt,
@@ -119,77 +131,142 @@ func resourceOvcMachine() *schema.Resource {
 							Optional: true,
 							Computed: true,
 						},
-						"ip_address": {
+						"external_network_name": {
 							Type:     schema.TypeString,
-							Computed: true,
-						},
-					},
-				},
-			},
-			"disks": {
-				Type:     schema.TypeList,
-				Computed: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"status": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"name": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"description": {
-							Type:     schema.TypeString,
-							Computed: true,
+							Optional: true,
 						},
-						"type": {
+						"ip_address": {
 							Type:     schema.TypeString,
 							Computed: true,
 						},
-						"id": {
-							Type:     schema.TypeInt,
-							Computed: true,
-						},
-						"size_max": {
-							Type:     schema.TypeInt,
-							Computed: true,
-						},
 					},
 				},
 			},
+			"disks": machineDisksSchema(),
 			"userdata": {
 				Type:     schema.TypeString,
 				Optional: true,
 			},
+			"adopt_existing": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
 		},
 	}
 }
 
-func resourceOvcMachineExists(d *schema.ResourceData, m interface{}) (bool, error) {
-	client := m.(*ovc.Client)
-	machineID, err := strconv.Atoi(d.Id())
-	if err != nil {
-		return false, nil
+// machineCreateKeys are the arguments applied by creating the machine, the
+// arguments applied by the steps after creating it are kept out of a partial state
+var machineCreateKeys = []string{
+	"cloudspace_id", "name", "description", "size_id", "size", "memory", "vcpus",
+	"image_id", "image_name", "disksize", "userdata", "adopt_existing",
+}
+
+// resourceOvcMachineCustomizeDiff resolves image_name and size to their IDs at plan time.
+// They are only resolved for new machines or when they change, so renames on the G8 do not affect existing machines.
+func resourceOvcMachineCustomizeDiff(diff *schema.ResourceDiff, v interface{}) error {
+	client := v.(*providerMeta).client
+	cloudspaceKnown := diff.NewValueKnown("cloudspace_id")
+	cloudspaceID := diff.Get("cloudspace_id").(int)
+
+	if imageName := diff.Get("image_name").(string); imageName != "" && (diff.Id() == "" || diff.HasChange("image_name")) && cloudspaceKnown {
+		imageID, err := resolveImageID(client, cloudspaceID, imageName)
+		if err != nil {
+			return err
+		}
+		if diff.Id() != "" && imageID != diff.Get("image_id").(int) {
+			return fmt.Errorf("Cannot change Image on existing machine")
+		}
+		if err := diff.SetNew("image_id", imageID); err != nil {
+			return err
+		}
 	}
-	_, err = client.Machines.Get(machineID)
-	if err != nil {
-		return false, nil
+	if diff.Id() == "" && diff.Get("image_name").(string) == "" {
+		if _, ok := diff.GetOk("image_id"); !ok {
+			return fmt.Errorf("One of image_id or image_name must be set")
+		}
+	}
+	if diff.Id() != "" && diff.HasChange("image_id") {
+		return fmt.Errorf("Cannot change Image ID on existing machine")
+	}
+
+	if size := diff.Get("size").(string); size != "" && (diff.Id() == "" || diff.HasChange("size")) && cloudspaceKnown {
+		sizeID, err := resolveSizeID(client, cloudspaceID, size)
+		if err != nil {
+			return err
+		}
+		if sizeID != diff.Get("size_id").(int) {
+			if err := diff.SetNew("size_id", sizeID); err != nil {
+				return err
+			}
+			if diff.Id() != "" {
+				diff.SetNewComputed("memory")
+				diff.SetNewComputed("vcpus")
+			}
+		}
+	}
+
+	if diff.HasChange("interfaces") {
+		if err := conflictingInterfaces(diff); err != nil {
+			return err
+		}
 	}
-	return true, nil
+	if diff.HasChange("interfaces") && cloudspaceKnown {
+		// validate the external network names, they are resolved when the interfaces are attached
+		for _, nici := range diff.Get("interfaces").([]interface{}) {
+			if nic, ok := nici.(map[string]interface{}); ok && nic["external_network_name"].(string) != "" {
+				if _, err := resolveExternalNetworkID(client, cloudspaceID, nic["external_network_name"].(string)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// conflictingInterfaces fails for interfaces configured with both network_id and external_network_name.
+// The network_id of an interface is kept from the state when it is not configured, so only
+// a network_id that differs from the one in the state counts as configured.
+func conflictingInterfaces(diff *schema.ResourceDiff) error {
+	old, _ := diff.GetChange("interfaces")
+	oldNics := old.([]interface{})
+	for i, nici := range diff.Get("interfaces").([]interface{}) {
+		nic, ok := nici.(map[string]interface{})
+		if !ok || nic["external_network_name"].(string) == "" || nic["network_id"].(int) == 0 {
+			continue
+		}
+		if i < len(oldNics) {
+			if oldNic, ok := oldNics[i].(map[string]interface{}); ok && oldNic["network_id"] == nic["network_id"] {
+				continue
+			}
+		}
+		return fmt.Errorf("interfaces.%d: network_id and external_network_name can not both be set", i)
+	}
+	return nil
 }
 
 func resourceOvcMachineRead(d *schema.ResourceData, m interface{}) error {
-	client := m.(*ovc.Client)
+	if ok, err := resumeCreate(d, m.(*providerMeta).api, idFromResult); !ok || err != nil {
+		return err
+	}
+	client := m.(*providerMeta).client
 	machineID, err := strconv.Atoi(d.Id())
 	if err != nil {
-		return nil
+		return err
 	}
 	machineInfo, err := client.Machines.Get(machineID)
 	if err != nil {
-		log.Println("machine not found error")
+		if isNotFound(err) {
+			log.Printf("[WARN] Machine %s not found, removing it from state", d.Id())
+			d.SetId("")
+			return nil
+		}
+		return err
+	}
+	if machineInfo.Status == "DESTROYED" {
+		log.Printf("[WARN] Machine %s is destroyed, removing it from state", d.Id())
 		d.SetId("")
-		log.Println("machine id na read:" + d.Id())
 		return nil
 	}
 	d.Set("hostname", machineInfo.Hostname)
@@ -207,13 +284,14 @@ func resourceOvcMachineRead(d *schema.ResourceData, m interface{}) error {
 	d.Set("size_id", machineInfo.SizeID)
 	d.Set("vcpus", machineInfo.Vcpus)
 	d.Set("disks", flattenDisks(machineInfo))
-	d.Set("interfaces", flattenNics(machineInfo))
+	d.Set("interfaces", withExternalNetworkNames(flattenNics(machineInfo), d.Get("interfaces").([]interface{})))
 
 	return nil
 }
 
 func resourceOvcMachineCreate(d *schema.ResourceData, m interface{}) error {
-	client := m.(*ovc.Client)
+	client, ctx, cancel := m.(*providerMeta).withTimeout(d.Timeout(schema.TimeoutCreate))
+	defer cancel()
 	machineConfig := ovc.MachineConfig{}
 	machineConfig.CloudspaceID = d.Get("cloudspace_id").(int)
 	machineConfig.Name = d.Get("name").(string)
@@ -224,19 +302,64 @@ func resourceOvcMachineCreate(d *schema.ResourceData, m interface{}) error {
 	machineConfig.Memory = d.Get("memory").(int)
 	machineConfig.Vcpus = d.Get("vcpus").(int)
 	machineConfig.Userdata = d.Get("userdata").(string)
-	machineID, err := client.Machines.Create(&machineConfig)
-	if err != nil {
-		return err
+	if imageName := d.Get("image_name").(string); machineConfig.ImageID == 0 && imageName != "" {
+		imageID, err := resolveImageID(client, machineConfig.CloudspaceID, imageName)
+		if err != nil {
+			return err
+		}
+		machineConfig.ImageID = imageID
+		d.Set("image_id", imageID)
+	}
+	if size := d.Get("size").(string); machineConfig.SizeID == 0 && size != "" {
+		sizeID, err := resolveSizeID(client, machineConfig.CloudspaceID, size)
+		if err != nil {
+			return err
+		}
+		machineConfig.SizeID = sizeID
+	}
+	var existing *ovc.MachineInfo
+	if d.Get("adopt_existing").(bool) {
+		var err error
+		if existing, err = findAdoptableMachine(client, &machineConfig); err != nil {
+			return err
+		}
+	}
+	var machineID int
+	if existing != nil {
+		log.Printf("[INFO] Adopting existing machine %d named %s", existing.ID, existing.Name)
+		machineID = existing.ID
+	} else {
+		var err error
+		machineID, err = client.Machines.Create(&machineConfig)
+		if err != nil {
+			return recordInterruptedCreate(d, err)
+		}
+		log.Printf("[DEBUG] New machine ID: %d\n", machineID)
 	}
-	log.Printf("[DEBUG] New machine ID: %d\n", machineID)
 	d.SetId(strconv.Itoa(machineID))
 	log.Printf("[DEBUG] Resource machine ID: %s\n", d.Id())
+
+	keys := []lockKey{machineKey(machineID)}
+	if d.Get("act_as_default_gateway").(bool) {
+		keys = append(keys, cloudspaceKey(machineConfig.CloudspaceID))
+	}
+	unlock, err := m.(*providerMeta).lock(ctx, keys...)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	// when a step below fails, its arguments are missing from the state and the next apply retries them
+	d.Partial(true)
+	for _, key := range machineCreateKeys {
+		d.SetPartial(key)
+	}
 	// Set IOPS boot disk
 	iops := d.Get("iops")
 	if iops != nil {
 		bootDiskID, err := GetBootDiskID(client, machineID)
 		if err != nil {
-			return err
+			return machineStepFailed(machineID, "iops", err)
 		}
 		diskConfig := &ovc.DiskConfig{
 			DiskID: bootDiskID,
@@ -244,13 +367,19 @@ func resourceOvcMachineCreate(d *schema.ResourceData, m interface{}) error {
 		}
 		err = client.Disks.Update(diskConfig)
 		if err != nil {
-			return err
+			return machineStepFailed(machineID, "iops", err)
 		}
 	}
+	d.SetPartial("iops")
 	if v, ok := d.GetOk("interfaces"); ok {
 		// attach to external networks
 		nics := v.([]interface{})
-		for _, nici := range nics {
+		if err := resolveInterfaceNetworks(client, machineConfig.CloudspaceID, nics); err != nil {
+			return machineStepFailed(machineID, "interfaces", err)
+		}
+		// keep the resolved network IDs so the names are kept when the machine is read
+		d.Set("interfaces", nics)
+		for _, nici := range missingNics(nics, existing) {
 			var networkID int
 			if nici != nil {
 				nic := nici.(map[string]interface{})
@@ -260,46 +389,145 @@ func resourceOvcMachineCreate(d *schema.ResourceData, m interface{}) error {
 				}
 			}
 			if err := client.Machines.AddExternalIP(machineID, networkID); err != nil {
-				return err
+				return machineStepFailed(machineID, "interfaces", err)
 			}
 		}
+		d.SetPartial("interfaces")
 	}
 	if d.Get("act_as_default_gateway").(bool) {
 		// Get machine private network IP
 		machineInfo, err := client.Machines.Get(machineID)
 		if err != nil {
-			return err
+			return machineStepFailed(machineID, "act_as_default_gateway", err)
 		}
 		var privateIP string
 		if len(machineInfo.Interfaces) > 0 && machineInfo.Interfaces[0].Type == "bridge" {
 			privateIP = machineInfo.Interfaces[0].IPAddress
 		}
 		if len(privateIP) == 0 {
-			return fmt.Errorf("[ERROR] Cannot set Machine %s as default gateway of Cloudspace %v: the Machine has no private network IP set", machineInfo.Name, machineInfo.CloudspaceID)
+			return machineStepFailed(machineID, "act_as_default_gateway", fmt.Errorf("Cannot set Machine %s as default gateway of Cloudspace %v: the Machine has no private network IP set", machineInfo.Name, machineInfo.CloudspaceID))
 		}
 		// set VM as default gateway of the parent cloudspace
 		if err := client.CloudSpaces.SetDefaultGateway(machineConfig.CloudspaceID, privateIP); err != nil {
-			return err
+			return machineStepFailed(machineID, "act_as_default_gateway", err)
 		}
 	}
+	d.SetPartial("act_as_default_gateway")
 	if v, ok := d.GetOk("disk_id"); ok {
-		diskIDInt := v.(int)
-		client.Machines.Stop(machineID, false)
-		client.Machines.Start(machineID, diskIDInt)
+		if err := restartFromDisk(client, machineID, v.(int)); err != nil {
+			return machineStepFailed(machineID, "disk_id", err)
+		}
 	}
+	d.Partial(false)
 	return resourceOvcMachineRead(d, m)
 }
 
+// machineStepFailed returns the error of a step after creating the machine. The arguments of the step and
+// the ones after it are not in the partial state, so the next apply retries them.
+func machineStepFailed(machineID int, step string, err error) error {
+	return fmt.Errorf("machine %d was created, but setting %s failed: %w", machineID, step, err)
+}
+
+// restartFromDisk stops the machine and starts it from the given disk, or from its boot disk when diskID is 0
+func restartFromDisk(client *ovc.Client, machineID int, diskID int) error {
+	if err := client.Machines.Stop(machineID, false); err != nil {
+		return err
+	}
+	return client.Machines.Start(machineID, diskID)
+}
+
+// findAdoptableMachine returns the machine with the name of the new machine in its cloudspace,
+// or nil when there is none. It fails when the machine does not match the configuration, see adoptionMismatches.
+func findAdoptableMachine(client *ovc.Client, machineConfig *ovc.MachineConfig) (*ovc.MachineInfo, error) {
+	machineInfo, err := client.Machines.GetByName(machineConfig.Name, machineConfig.CloudspaceID)
+	if err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	switch machineInfo.Status {
+	case "DELETED", "DESTROYED":
+		return nil, nil
+	}
+	if mismatches := adoptionMismatches(machineConfig, machineInfo); len(mismatches) > 0 {
+		return nil, fmt.Errorf("machine %s already exists in cloudspace %d with %s, rename the machine or import the existing one",
+			machineInfo.Name, machineConfig.CloudspaceID, strings.Join(mismatches, ", "))
+	}
+	return machineInfo, nil
+}
+
+// adoptionMismatches lists how an existing machine differs from the image, size and boot disk size of the configuration
+func adoptionMismatches(machineConfig *ovc.MachineConfig, machineInfo *ovc.MachineInfo) []string {
+	var mismatches []string
+	if machineInfo.ImageID != machineConfig.ImageID {
+		mismatches = append(mismatches, fmt.Sprintf("image %d instead of %d", machineInfo.ImageID, machineConfig.ImageID))
+	}
+	if machineConfig.SizeID != 0 && machineInfo.SizeID != machineConfig.SizeID {
+		mismatches = append(mismatches, fmt.Sprintf("size %d instead of %d", machineInfo.SizeID, machineConfig.SizeID))
+	}
+	if machineConfig.Memory != 0 && machineInfo.Memory != machineConfig.Memory {
+		mismatches = append(mismatches, fmt.Sprintf("%d MB memory instead of %d", machineInfo.Memory, machineConfig.Memory))
+	}
+	if machineConfig.Vcpus != 0 && machineInfo.Vcpus != machineConfig.Vcpus {
+		mismatches = append(mismatches, fmt.Sprintf("%d vcpus instead of %d", machineInfo.Vcpus, machineConfig.Vcpus))
+	}
+	for _, disk := range machineInfo.Disks {
+		if disk.Type == "B" && disk.SizeMax != machineConfig.Disksize {
+			mismatches = append(mismatches, fmt.Sprintf("a %d GB boot disk instead of %d", disk.SizeMax, machineConfig.Disksize))
+		}
+	}
+	return mismatches
+}
+
+// missingNics returns the configured interfaces the machine is not attached to yet.
+// An interface without network_id is matched by any public interface of the machine.
+func missingNics(nics []interface{}, machineInfo *ovc.MachineInfo) []interface{} {
+	if machineInfo == nil {
+		return nics
+	}
+	unused := flattenNics(machineInfo)
+	var missing []interface{}
+	for _, nici := range nics {
+		var networkID int
+		if nic, ok := nici.(map[string]interface{}); ok && nic["network_id"] != nil {
+			networkID = nic["network_id"].(int)
+		}
+		matched := false
+		for i, nic := range unused {
+			if networkID == 0 || nic["network_id"] == networkID {
+				unused = append(unused[:i], unused[i+1:]...)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			missing = append(missing, nici)
+		}
+	}
+	return missing
+}
+
 func resourceOvcMachineUpdate(d *schema.ResourceData, m interface{}) error {
 
 	var err error
-	client := m.(*ovc.Client)
+	client, ctx, cancel := m.(*providerMeta).withTimeout(d.Timeout(schema.TimeoutUpdate))
+	defer cancel()
 	machineConfig := ovc.MachineConfig{}
 	machineConfig.MachineID = d.Id()
 	machineIDInt, err := strconv.Atoi(machineConfig.MachineID)
 	if err != nil {
 		return err
 	}
+	keys := []lockKey{machineKey(machineIDInt)}
+	if d.HasChange("act_as_default_gateway") {
+		keys = append(keys, cloudspaceKey(d.Get("cloudspace_id").(int)))
+	}
+	unlock, err := m.(*providerMeta).lock(ctx, keys...)
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	if d.HasChange("name") {
 		machineConfig.Name = d.Get("name").(string)
 	}
@@ -310,9 +538,13 @@ func resourceOvcMachineUpdate(d *schema.ResourceData, m interface{}) error {
 			return err
 		}
 	}
-	if d.HasChange("memory") || d.HasChange("vcpus") {
-		machineConfig.Memory = d.Get("memory").(int)
-		machineConfig.Vcpus = d.Get("vcpus").(int)
+	if d.HasChange("size_id") || d.HasChange("memory") || d.HasChange("vcpus") {
+		if d.HasChange("size_id") {
+			machineConfig.SizeID = d.Get("size_id").(int)
+		} else {
+			machineConfig.Memory = d.Get("memory").(int)
+			machineConfig.Vcpus = d.Get("vcpus").(int)
+		}
 		_, err = client.Machines.Resize(&machineConfig)
 		if err != nil {
 			return err
@@ -346,6 +578,10 @@ func resourceOvcMachineUpdate(d *schema.ResourceData, m interface{}) error {
 			old, new := d.GetChange("interfaces")
 			oldNics := old.([]interface{})
 			newNics := new.([]interface{})
+			if err := resolveInterfaceNetworks(client, d.Get("cloudspace_id").(int), newNics); err != nil {
+				return err
+			}
+			d.Set("interfaces", newNics)
 			oldNetworks := countAttachedNetworks(oldNics)
 			newNetworks := countAttachedNetworks(newNics)
 
@@ -447,12 +683,31 @@ func resourceOvcMachineUpdate(d *schema.ResourceData, m interface{}) error {
 		if v, ok := d.GetOk("disk_id"); ok {
 			diskIDInt = v.(int)
 		}
-		client.Machines.Stop(machineIDInt, false)
-		client.Machines.Start(machineIDInt, diskIDInt)
+		if err := restartFromDisk(client, machineIDInt, diskIDInt); err != nil {
+			return err
+		}
 	}
 	return resourceOvcMachineRead(d, m)
 }
 
+// withExternalNetworkNames keeps the external network names of the configured interfaces in the state
+func withExternalNetworkNames(nics []map[string]interface{}, configured []interface{}) []map[string]interface{} {
+	names := make(map[int]string)
+	for _, nici := range configured {
+		if nic, ok := nici.(map[string]interface{}); ok {
+			if name, _ := nic["external_network_name"].(string); name != "" {
+				names[nic["network_id"].(int)] = name
+			}
+		}
+	}
+	for _, nic := range nics {
+		if name, ok := names[nic["network_id"].(int)]; ok {
+			nic["external_network_name"] = name
+		}
+	}
+	return nics
+}
+
 func countAttachedNetworks(nics []interface{}) map[int][]string {
 	attachedNetworks := make(map[int][]string)
 	for _, nicInterface := range nics {
@@ -469,14 +724,59 @@ func countAttachedNetworks(nics []interface{}) map[int][]string {
 }
 
 func resourceOvcMachineDelete(d *schema.ResourceData, m interface{}) error {
-	client := m.(*ovc.Client)
+	if ok, err := resumeBeforeDelete(d, m.(*providerMeta).api, idFromResult); !ok || err != nil {
+		return err
+	}
+	client, ctx, cancel := m.(*providerMeta).withTimeout(d.Timeout(schema.TimeoutDelete))
+	defer cancel()
 	machineID, err := strconv.Atoi(d.Id())
 	if err != nil {
 		return err
 	}
+	unlock, err := m.(*providerMeta).lock(ctx, machineKey(machineID))
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	return client.Machines.Delete(machineID, true)
 }
 
+// machineDisksSchema describes the disks of a machine as flattened by flattenDisks
+func machineDisksSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeList,
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"status": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"name": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"description": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"type": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"id": {
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+				"size_max": {
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+			},
+		},
+	}
+}
+
 func flattenDisks(machineInfo *ovc.MachineInfo) []map[string]interface{} {
 	result := make([]map[string]interface{}, 0, 1)
 
@@ -0,0 +1,165 @@
+package ovc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gig-tech/ovc-sdk-go/v3/ovc"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestMissingNics(t *testing.T) {
+	nics := []interface{}{
+		map[string]interface{}{"network_id": 1},
+		map[string]interface{}{"network_id": 2},
+		map[string]interface{}{"network_id": 0},
+	}
+	if missing := missingNics(nics, nil); len(missing) != 3 {
+		t.Errorf("new machine: %d missing interfaces, want 3", len(missing))
+	}
+
+	machineInfo := &ovc.MachineInfo{Interfaces: []ovc.NIC{
+		{Type: "bridge", NetworkID: 2},
+		{Type: "PUBLIC", NetworkID: 2},
+		{Type: "PUBLIC", NetworkID: 5},
+	}}
+	missing := missingNics(nics, machineInfo)
+	if len(missing) != 1 || missing[0].(map[string]interface{})["network_id"] != 1 {
+		t.Errorf("adopted machine: missing interfaces %v, want network 1", missing)
+	}
+}
+
+func TestAdoptionMismatches(t *testing.T) {
+	machineInfo := &ovc.MachineInfo{ImageID: 3, SizeID: 2, Memory: 2048, Vcpus: 2, Disks: []ovc.MachineDisk{
+		{Type: "B", SizeMax: 20},
+		{Type: "D", SizeMax: 100},
+	}}
+	if mismatches := adoptionMismatches(&ovc.MachineConfig{ImageID: 3, SizeID: 2, Disksize: 20}, machineInfo); len(mismatches) != 0 {
+		t.Errorf("matching machine: mismatches %v", mismatches)
+	}
+	if mismatches := adoptionMismatches(&ovc.MachineConfig{ImageID: 3, Memory: 2048, Vcpus: 2, Disksize: 20}, machineInfo); len(mismatches) != 0 {
+		t.Errorf("matching memory and vcpus: mismatches %v", mismatches)
+	}
+	mismatches := adoptionMismatches(&ovc.MachineConfig{Memory: 4096, Vcpus: 2, Disksize: 10}, machineInfo)
+	if len(mismatches) != 3 {
+		t.Errorf("mismatches %v, want image, memory and disk size", mismatches)
+	}
+}
+
+func TestFailedStepAfterCreateKeepsPartialState(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != taskEndpoint {
+			if r.URL.Path == "/cloudapi/disks/limitIO" {
+				w.WriteHeader(http.StatusInternalServerError)
+				fmt.Fprint(w, `"IO limits are not supported"`)
+				return
+			}
+			// the task GUID is the endpoint that started it
+			fmt.Fprintf(w, "%q", r.URL.Path)
+			return
+		}
+		var task struct {
+			GUID string `json:"taskguid"`
+		}
+		json.NewDecoder(r.Body).Decode(&task)
+		switch task.GUID {
+		case machinesListEndpoint:
+			fmt.Fprint(w, `[true, []]`)
+		case "/cloudapi/machines/create":
+			fmt.Fprint(w, `[true, 1]`)
+		default:
+			fmt.Fprint(w, `[true, {"id": 1, "name": "vm", "cloudspaceid": 1, "memory": 1024, "vcpus": 1, "disks": [{"id": 10, "type": "B", "sizeMax": 10}]}]`)
+		}
+	}))
+	defer server.Close()
+
+	meta := newProviderMeta(newTestAPIClient(t, context.Background(), server, retryPolicy{}), "ci")
+	r := resourceOvcMachine()
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"cloudspace_id": 1,
+		"name":          "vm",
+		"image_id":      3,
+		"memory":        1024,
+		"vcpus":         1,
+		"disksize":      10,
+		"iops":          500,
+	})
+	diff, err := r.Diff(nil, config, meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, err := r.Apply(nil, diff, meta)
+	if err == nil || !strings.Contains(err.Error(), "machine 1 was created, but setting iops failed") {
+		t.Fatalf("expected the error of the iops step, got %v", err)
+	}
+	if state == nil || state.ID != "1" {
+		t.Fatalf("state = %v", state)
+	}
+	if state.Attributes["name"] != "vm" || state.Attributes["disksize"] != "10" {
+		t.Errorf("the arguments of the created machine are missing from the state: %v", state.Attributes)
+	}
+	// the failed step is retried by the next apply
+	if _, ok := state.Attributes["iops"]; ok {
+		t.Errorf("iops is in the state: %v", state.Attributes)
+	}
+}
+
+func TestInterfaceWithNetworkIDAndName(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != taskEndpoint {
+			fmt.Fprintf(w, "%q", r.URL.Path)
+			return
+		}
+		var task struct {
+			GUID string `json:"taskguid"`
+		}
+		json.NewDecoder(r.Body).Decode(&task)
+		if task.GUID == "/cloudapi/cloudspaces/get" {
+			fmt.Fprint(w, `[true, {"id": 1, "accountId": 2}]`)
+			return
+		}
+		fmt.Fprint(w, `[true, [{"id": 5, "name": "public", "accountId": 0}]]`)
+	}))
+	defer server.Close()
+
+	meta := newProviderMeta(newTestAPIClient(t, context.Background(), server, retryPolicy{}), "ci")
+	config := func(nic map[string]interface{}) *terraform.ResourceConfig {
+		return terraform.NewResourceConfigRaw(map[string]interface{}{
+			"cloudspace_id": 1,
+			"name":          "vm",
+			"image_id":      3,
+			"memory":        1024,
+			"vcpus":         1,
+			"disksize":      10,
+			"interfaces":    []interface{}{nic},
+		})
+	}
+	r := resourceOvcMachine()
+	if _, err := r.Diff(nil, config(map[string]interface{}{"network_id": 5, "external_network_name": "public"}), meta); err == nil {
+		t.Error("expected an error for an interface with network_id and external_network_name")
+	}
+
+	// the network_id of an interface configured by name is in the state
+	state := &terraform.InstanceState{ID: "1", Attributes: map[string]string{
+		"cloudspace_id":                      "1",
+		"name":                               "vm",
+		"image_id":                           "3",
+		"memory":                             "1024",
+		"vcpus":                              "1",
+		"disksize":                           "10",
+		"interfaces.#":                       "1",
+		"interfaces.0.network_id":            "5",
+		"interfaces.0.external_network_name": "public",
+	}}
+	if _, err := r.Diff(state, config(map[string]interface{}{"external_network_name": "public"}), meta); err != nil {
+		t.Errorf("interface configured by name: %v", err)
+	}
+	if _, err := r.Diff(state, config(map[string]interface{}{"network_id": 6, "external_network_name": "public"}), meta); err == nil {
+		t.Error("expected an error for a changed network_id next to external_network_name")
+	}
+}
@@ -0,0 +1,74 @@
+package ovc
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform/helper/schema"
+)
+
+// pendingTaskPrefix marks the ID of a resource whose create was interrupted while its G8 task was running
+const pendingTaskPrefix = "task-"
+
+// recordInterruptedCreate keeps the GUID of the task of an interrupted create as the ID of the resource.
+// Terraform saves the resource as tainted, the next refresh takes the ID of the created object from the
+// task result, see resumeCreate. The object is then replaced by the next apply unless it is untainted,
+// the G8 does not end up with a second object next to it.
+func recordInterruptedCreate(d *schema.ResourceData, err error) error {
+	if taskID, ok := interruptedTask(err); ok {
+		log.Printf("[WARN] Create was interrupted, the G8 task %s is kept in the state to resume from", taskID)
+		d.SetId(pendingTaskPrefix + taskID)
+	}
+	return err
+}
+
+// createResult applies the result of the create task of a resource to the resource data
+type createResult func(d *schema.ResourceData, result []byte) error
+
+// idFromResult sets the ID of a resource whose create task returns it, like machines, cloudspaces and disks
+func idFromResult(d *schema.ResourceData, result []byte) error {
+	d.SetId(strings.Trim(string(result), `"`))
+	return nil
+}
+
+// resumeCreate applies the result of the task recorded by recordInterruptedCreate with apply, which replaces the ID.
+// It returns false when there is no object to read: the task is still running, in which case the
+// ID is kept, or the task failed, in which case the resource is removed from the state.
+// The task result is never logged, it can hold secrets like the pre-shared key of an ipsec tunnel.
+func resumeCreate(d *schema.ResourceData, api *apiClient, apply createResult) (bool, error) {
+	if !strings.HasPrefix(d.Id(), pendingTaskPrefix) {
+		return true, nil
+	}
+	taskID := strings.TrimPrefix(d.Id(), pendingTaskPrefix)
+	result, done, err := api.taskResult(taskID)
+	switch {
+	case err != nil && done:
+		log.Printf("[WARN] Interrupted create task %s failed, removing the resource from state: %v", taskID, err)
+		d.SetId("")
+		return false, nil
+	case isTaskLost(err):
+		return false, fmt.Errorf("%w, import the object of the interrupted create or remove the resource from the state", err)
+	case err != nil:
+		return false, err
+	case !done:
+		log.Printf("[WARN] Interrupted create task %s is still running on the G8", taskID)
+		return false, nil
+	}
+	if err := apply(d, result); err != nil {
+		return false, fmt.Errorf("error applying the result of create task %s: %w", taskID, err)
+	}
+	log.Printf("[INFO] Resumed interrupted create task %s", taskID)
+	return true, nil
+}
+
+// resumeBeforeDelete is resumeCreate for the delete of a resource, which can not proceed while the task is still running.
+// It returns false when the resource was removed from the state.
+func resumeBeforeDelete(d *schema.ResourceData, api *apiClient, apply createResult) (bool, error) {
+	ok, err := resumeCreate(d, api, apply)
+	if err != nil || ok || d.Id() == "" {
+		return ok, err
+	}
+	return false, fmt.Errorf("the create task %s is still running on the G8, try again once it completed",
+		strings.TrimPrefix(d.Id(), pendingTaskPrefix))
+}
@@ -0,0 +1,178 @@
+package ovc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gig-tech/ovc-sdk-go/v3/ovc"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/terraform"
+)
+
+func TestInterruptedCreateIsResumed(t *testing.T) {
+	var done int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != taskEndpoint {
+			fmt.Fprint(w, `"guid-1"`)
+			return
+		}
+		if atomic.LoadInt32(&done) == 0 {
+			fmt.Fprint(w, `[]`)
+			return
+		}
+		fmt.Fprint(w, `[true, 42]`)
+	}))
+	defer server.Close()
+
+	ctx, stop := context.WithCancel(context.Background())
+	api := newTestAPIClient(t, ctx, server, retryPolicy{})
+	d := resourceOvcMachine().TestResourceData()
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		stop()
+	}()
+	_, err := api.post("/cloudapi/machines/create", nil, ovc.OperationalActionTimeout)
+	if taskID, ok := interruptedTask(err); !ok || taskID != "guid-1" {
+		t.Fatalf("expected interrupted task guid-1, got %v", err)
+	}
+	recordInterruptedCreate(d, err)
+	if d.Id() != "task-guid-1" {
+		t.Fatalf("id = %q", d.Id())
+	}
+
+	api = api.withContext(context.Background())
+	if ok, err := resumeCreate(d, api, idFromResult); ok || err != nil || d.Id() != "task-guid-1" {
+		t.Fatalf("running task: ok = %v, err = %v, id = %q", ok, err, d.Id())
+	}
+	atomic.StoreInt32(&done, 1)
+	if ok, err := resumeCreate(d, api, idFromResult); !ok || err != nil || d.Id() != "42" {
+		t.Fatalf("completed task: ok = %v, err = %v, id = %q", ok, err, d.Id())
+	}
+}
+
+func TestInterruptedIpsecCreateKeepsThePSK(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `[true, "s3cret"]`)
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	api := newTestAPIClient(t, context.Background(), server, retryPolicy{})
+	d := resourceIpsec().TestResourceData()
+	d.Set("remote_public_ip", "192.0.2.1")
+	d.Set("remote_private_network", "10.1.0.0/24")
+	d.SetId(pendingTaskPrefix + "guid-1")
+	if ok, err := resumeCreate(d, api, ipsecFromResult); !ok || err != nil {
+		t.Fatalf("ok = %v, err = %v", ok, err)
+	}
+	if d.Get("psk") != "s3cret" {
+		t.Errorf("psk = %q", d.Get("psk"))
+	}
+	if d.Id() != "192.0.2.1:10.1.0.0/24" {
+		t.Errorf("id = %q", d.Id())
+	}
+	if strings.Contains(logs.String(), "s3cret") {
+		t.Errorf("the pre-shared key was logged: %s", logs.String())
+	}
+}
+
+func TestInterruptedCreateStaysTainted(t *testing.T) {
+	var done int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != taskEndpoint {
+			if r.URL.Path == "/cloudapi/machines/addDisk" {
+				fmt.Fprint(w, `"guid-1"`)
+				return
+			}
+			fmt.Fprintf(w, "%q", r.URL.Path)
+			return
+		}
+		var task struct {
+			GUID string `json:"taskguid"`
+		}
+		json.NewDecoder(r.Body).Decode(&task)
+		switch {
+		case task.GUID != "guid-1":
+			fmt.Fprint(w, `[true, {"id": 42, "status": "ASSIGNED"}]`)
+		case atomic.LoadInt32(&done) == 0:
+			fmt.Fprint(w, `[]`)
+		default:
+			fmt.Fprint(w, `[true, 42]`)
+		}
+	}))
+	defer server.Close()
+
+	ctx, stop := context.WithCancel(context.Background())
+	meta := newProviderMeta(newTestAPIClient(t, ctx, server, retryPolicy{}), "ci")
+	r := resourceOvcDisk()
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"machine_id":  1,
+		"disk_name":   "data",
+		"description": "data",
+		"size":        10,
+		"type":        "D",
+	})
+	diff, err := r.Diff(nil, config, meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		stop()
+	}()
+	state, err := r.Apply(nil, diff, meta)
+	if _, ok := interruptedTask(err); !ok || state == nil || state.ID != "task-guid-1" {
+		t.Fatalf("expected an interrupted create recorded in the state, got %v, %v", state, err)
+	}
+
+	// Terraform taints a resource whose create failed, the object is replaced unless it is untainted
+	state.Tainted = true
+	atomic.StoreInt32(&done, 1)
+	meta = newProviderMeta(newTestAPIClient(t, context.Background(), server, retryPolicy{}), "ci")
+	state, err = r.Refresh(state, meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.ID != "42" || !state.Tainted {
+		t.Errorf("refresh: id = %q, tainted = %v, want the created disk still tainted", state.ID, state.Tainted)
+	}
+}
+
+func TestDeleteWaitsForInterruptedCreate(t *testing.T) {
+	var deletes int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != taskEndpoint {
+			atomic.AddInt32(&deletes, 1)
+			fmt.Fprint(w, `"guid-2"`)
+			return
+		}
+		fmt.Fprint(w, `[]`)
+	}))
+	defer server.Close()
+
+	meta := newProviderMeta(newTestAPIClient(t, context.Background(), server, retryPolicy{}), "ci")
+	for name, r := range map[string]*schema.Resource{"ovc_port_forwarding": resourcePortForwarding(), "ovc_ipsec": resourceIpsec()} {
+		d := r.TestResourceData()
+		d.SetId(pendingTaskPrefix + "guid-1")
+		if err := r.Delete(d, meta); err == nil || d.Id() != "task-guid-1" {
+			t.Errorf("%s: expected the delete to fail while the create task runs, got %v", name, err)
+		}
+	}
+	if deletes != 0 {
+		t.Errorf("%d delete calls were sent while the create task was running", deletes)
+	}
+}